@@ -0,0 +1,179 @@
+package dirs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Severity classifies a Finding.
+type Severity int
+
+const (
+	// SeverityOK means the directory passed every check.
+	SeverityOK Severity = iota
+	// SeverityInfo is a noteworthy but harmless condition, such as a
+	// directory that does not exist yet.
+	SeverityInfo
+	// SeverityWarning is a condition that may cause problems, such as
+	// overly permissive permissions.
+	SeverityWarning
+	// SeverityError is a condition that will cause writes to fail or go to
+	// the wrong place.
+	SeverityError
+)
+
+var severityNames = [...]string{
+	SeverityOK:      "ok",
+	SeverityInfo:    "info",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+func (s Severity) String() string {
+	if s < SeverityOK || s > SeverityError {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// MarshalText implements encoding.TextMarshaler using the severity's name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Finding is the result of one check of one directory.
+type Finding struct {
+	Kind     Kind     `json:"kind"`
+	Path     string   `json:"path,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (f Finding) String() string {
+	if f.Path == "" {
+		return fmt.Sprintf("%s: %s: %s", f.Severity, f.Kind, f.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", f.Severity, f.Kind, f.Path, f.Message)
+}
+
+// MaxSeverity returns the highest severity among findings, or SeverityOK if
+// there are none.
+func MaxSeverity(findings []Finding) Severity {
+	worst := SeverityOK
+	for _, f := range findings {
+		if f.Severity > worst {
+			worst = f.Severity
+		}
+	}
+	return worst
+}
+
+// Check resolves the given directory kinds of l, or every kind if none are
+// given, and inspects the results: whether each resolved, is absolute,
+// exists, is a directory rather than a file or a dangling symlink, is
+// writable by the current user and has safe permissions. Every kind yields
+// at least one Finding, in the order given.
+func Check(l Locator, kinds ...Kind) []Finding {
+	if len(kinds) == 0 {
+		kinds = Kinds()
+	}
+	var findings []Finding
+	for _, k := range kinds {
+		findings = append(findings, checkDir(l, k)...)
+	}
+	return findings
+}
+
+func checkDir(l Locator, kind Kind) []Finding {
+	path, err := l.Dir(kind)
+	finding := func(severity Severity, format string, args ...any) Finding {
+		return Finding{Kind: kind, Path: path, Severity: severity, Message: fmt.Sprintf(format, args...)}
+	}
+
+	// Values that were set but rejected, such as a relative XDG_CONFIG_HOME,
+	// are reported even though resolution fell back to a usable directory.
+	var findings []Finding
+	if r, ok := l.(interface {
+		Resolve(kind Kind) (Resolution, error)
+	}); ok {
+		res, _ := r.Resolve(kind)
+		if res.Source == SourceFallback {
+			findings = append(findings, finding(SeverityInfo, "using fallback %s", res.Detail))
+		}
+		// Derived directories inherit the problems of their bases.
+		for cur := &res; cur != nil; cur = cur.Base {
+			for _, c := range cur.Skipped {
+				if c.Value != "" {
+					findings = append(findings, finding(SeverityWarning, "ignored %s %s=%q: %s", c.Source, c.Detail, c.Value, c.Reason))
+				}
+			}
+		}
+	}
+	return append(findings, inspectPath(kind, path, err, ownerOf(l), finding)...)
+}
+
+// inspectPath inspects the outcome of resolving kind. The directory should
+// belong to owner, or to the current user if owner is nil.
+func inspectPath(kind Kind, path string, err error, owner *passwdEntry, finding func(Severity, string, ...any) Finding) []Finding {
+	switch {
+	case errors.Is(err, ErrNotSupported):
+		return []Finding{finding(SeverityInfo, "not supported on this platform")}
+	case errors.Is(err, ErrNotSet):
+		return []Finding{finding(SeverityWarning, "%v", err)}
+	case err != nil:
+		return []Finding{finding(SeverityError, "%v", err)}
+	case path == "":
+		return []Finding{finding(SeverityInfo, "not available")}
+	case !filepath.IsAbs(path):
+		return []Finding{finding(SeverityError, "path is not absolute and would resolve against the working directory")}
+	}
+
+	lfi, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Finding{finding(SeverityInfo, "does not exist yet")}
+	}
+	if err != nil {
+		return []Finding{finding(SeverityError, "cannot be inspected: %v", err)}
+	}
+	fi := lfi
+	if lfi.Mode()&fs.ModeSymlink != 0 {
+		target, _ := os.Readlink(path)
+		if fi, err = os.Stat(path); err != nil {
+			return []Finding{finding(SeverityError, "dangling symlink to %s", target)}
+		}
+	}
+	if !fi.IsDir() {
+		return []Finding{finding(SeverityError, "exists but is not a directory")}
+	}
+
+	var findings []Finding
+	// For another user's directories, access(2) would answer for the
+	// current user, typically root; permissionProblems checks the owner.
+	if owner == nil && !isWritable(path) {
+		severity := SeverityWarning
+		if isBaseKind(kind) {
+			severity = SeverityError
+		}
+		findings = append(findings, finding(severity, "not writable by the current user"))
+	}
+	for _, problem := range permissionProblems(kind, fi, owner) {
+		findings = append(findings, finding(SeverityWarning, "%s", problem))
+	}
+	if len(findings) == 0 {
+		findings = append(findings, finding(SeverityOK, "ok"))
+	}
+	return findings
+}
+
+// isBaseKind reports whether kind is one of the directories applications
+// write to, as opposed to the user's own folders such as Music.
+func isBaseKind(kind Kind) bool {
+	switch kind {
+	case Home, Cache, Config, Data, DataLocal, Executable, Preference, Runtime, State:
+		return true
+	}
+	return false
+}
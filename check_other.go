@@ -0,0 +1,31 @@
+//go:build !unix && !windows
+
+package dirs
+
+import (
+	"io/fs"
+	"os"
+)
+
+// isWritable reports whether the current user may create files in dir by
+// trying to, as the platform's permission model is not known here.
+func isWritable(dir string) bool {
+	f, err := os.CreateTemp(dir, ".dirs-check-*")
+	if err != nil {
+		return false
+	}
+	name := f.Name()
+	f.Close()
+	os.Remove(name)
+	return true
+}
+
+// permissionProblems is a no-op on platforms without Unix ownership.
+func permissionProblems(kind Kind, fi fs.FileInfo, owner *passwdEntry) []string {
+	return nil
+}
+
+// repairMode is a no-op on platforms without Unix ownership.
+func repairMode(dir string, fi fs.FileInfo, want fs.FileMode, uid int) error {
+	return nil
+}
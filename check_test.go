@@ -0,0 +1,106 @@
+package dirs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// locatorFunc adapts a function to the Locator interface.
+type locatorFunc func(kind Kind) (string, error)
+
+func (f locatorFunc) Dir(kind Kind) (string, error) {
+	return f(kind)
+}
+
+func TestCheck(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	good := filepath.Join(root, "good")
+	if err := os.Mkdir(good, 0o700); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+
+	paths := map[Kind]string{
+		Home:   good,
+		Cache:  "relative/cache",
+		Config: filepath.Join(root, "missing"),
+		Data:   file,
+	}
+	if runtime.GOOS != "windows" {
+		dangling := filepath.Join(root, "dangling")
+		if err := os.Symlink(filepath.Join(root, "nowhere"), dangling); err != nil {
+			t.Fatalf("Failed to create symlink: %v", err)
+		}
+		paths[State] = dangling
+
+		open := filepath.Join(root, "open")
+		if err := os.Mkdir(open, 0o700); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.Chmod(open, 0o777); err != nil {
+			t.Fatalf("Failed to chmod dir: %v", err)
+		}
+		paths[Runtime] = open
+	}
+
+	l := locatorFunc(func(kind Kind) (string, error) {
+		if kind == Template {
+			return "", &DirError{Kind: kind, Err: ErrNotSupported}
+		}
+		if path, ok := paths[kind]; ok {
+			return path, nil
+		}
+		return good, nil
+	})
+
+	findings := Check(l)
+	byKind := make(map[Kind][]Finding)
+	for _, f := range findings {
+		byKind[f.Kind] = append(byKind[f.Kind], f)
+	}
+	if len(byKind) != len(Kinds()) {
+		t.Errorf("Expected findings for all %d kinds, got %d", len(Kinds()), len(byKind))
+	}
+
+	expect := func(kind Kind, severity Severity, substr string) {
+		t.Helper()
+		for _, f := range byKind[kind] {
+			if f.Severity == severity && strings.Contains(f.Message, substr) {
+				return
+			}
+		}
+		t.Errorf("Expected a %s finding containing %q for %s, got %v", severity, substr, kind, byKind[kind])
+	}
+	expect(Home, SeverityOK, "ok")
+	expect(Cache, SeverityError, "not absolute")
+	expect(Config, SeverityInfo, "does not exist")
+	expect(Data, SeverityError, "not a directory")
+	expect(Template, SeverityInfo, "not supported")
+	if runtime.GOOS != "windows" {
+		expect(State, SeverityError, "dangling symlink")
+		expect(Runtime, SeverityWarning, "writable by other users")
+		expect(Runtime, SeverityWarning, "not 0700")
+	}
+
+	if MaxSeverity(findings) != SeverityError {
+		t.Errorf("Expected the worst severity to be error, got %s", MaxSeverity(findings))
+	}
+	if MaxSeverity(nil) != SeverityOK {
+		t.Error("Expected no findings to be ok")
+	}
+}
+
+func TestCheckKinds(t *testing.T) {
+	dir := t.TempDir()
+	l := locatorFunc(func(kind Kind) (string, error) { return dir, nil })
+	findings := Check(l, Config, Cache)
+	if len(findings) != 2 || findings[0].Kind != Config || findings[1].Kind != Cache {
+		t.Errorf("Expected findings for config and cache only, got %v", findings)
+	}
+}
@@ -0,0 +1,54 @@
+//go:build unix
+
+package dirs
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// isWritable reports whether the current user may create files in dir.
+func isWritable(dir string) bool {
+	const wOK = 0x2
+	return syscall.Access(dir, wOK) == nil
+}
+
+// permissionProblems describes the ways fi's ownership and mode are unsafe
+// for a directory of the given kind that should belong to owner, or to the
+// current user if owner is nil.
+func permissionProblems(kind Kind, fi fs.FileInfo, owner *passwdEntry) []string {
+	var problems []string
+	mode := fi.Mode()
+	uid, ok := fileUID(fi)
+	switch {
+	case !ok:
+	case owner == nil && uid != os.Getuid():
+		problems = append(problems, fmt.Sprintf("owned by uid %d, not the current user", uid))
+	case owner != nil && uid != owner.uid:
+		problems = append(problems, fmt.Sprintf("owned by uid %d, not %s (uid %d)", uid, owner.name, owner.uid))
+	case owner != nil && mode.Perm()&0o200 == 0:
+		problems = append(problems, fmt.Sprintf("mode %04o is not writable by %s", mode.Perm(), owner.name))
+	}
+	if mode.Perm()&0o022 != 0 && mode&fs.ModeSticky == 0 {
+		problems = append(problems, fmt.Sprintf("mode %04o is writable by other users", mode.Perm()))
+	}
+	if kind == Runtime && mode.Perm()&0o077 != 0 {
+		problems = append(problems, fmt.Sprintf("mode %04o is not 0700 as required for the runtime directory", mode.Perm()))
+	}
+	return problems
+}
+
+// repairMode removes the permission bits of the directory dir that exceed
+// want. Directories not owned by uid are reported, not changed.
+func repairMode(dir string, fi fs.FileInfo, want fs.FileMode, uid int) error {
+	extra := fi.Mode().Perm() &^ want
+	if extra == 0 {
+		return nil
+	}
+	if owner, ok := fileUID(fi); ok && owner != uid {
+		return fmt.Errorf("%w: %s has mode %04o and is owned by uid %d", ErrInsecure, dir, fi.Mode().Perm(), owner)
+	}
+	return os.Chmod(dir, fi.Mode().Perm()&^extra)
+}
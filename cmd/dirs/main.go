@@ -0,0 +1,272 @@
+// Command dirs prints the directories resolved by the dirs package.
+//
+// Usage:
+//
+//	dirs [-format plain|json|env|SHELL] [-prefix PREFIX] [-app name [-org organization] [-qualifier qualifier] [-env-prefix PREFIX]] [kind...]
+//
+// Without arguments every directory is printed. Kinds are named as accepted
+// by dirs.ParseKind, e.g. "cache", "data_local" or "ConfigDir". With -app the
+// application-scoped directories of dirs.ProjectDirs are printed instead.
+//
+// SHELL is any name accepted by dirs.ParseShell, such as bash, zsh, fish or
+// pwsh, and prints every directory as an export for that shell:
+//
+//	eval "$(dirs -format bash -prefix MYAPP_ -app MyApp)"
+//
+// The doctor subcommand checks the directories with dirs.Check and exits
+// with status 1 if any check fails:
+//
+//	dirs doctor [-format plain|json] [-app name ...] [kind...]
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ansrivas/dirs"
+)
+
+func main() {
+	os.Exit(run(dirs.NewDirs(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+type result struct {
+	kind dirs.Kind
+	path string
+	err  error
+}
+
+// appFlags holds the flags that select application-scoped directories.
+type appFlags struct {
+	app, org, qualifier, envPrefix string
+}
+
+func (f *appFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.app, "app", "", "use the directories of this application")
+	flags.StringVar(&f.org, "org", "", "organization of the application, used with -app")
+	flags.StringVar(&f.qualifier, "qualifier", "", "qualifier of the application, e.g. com, used with -app")
+	flags.StringVar(&f.envPrefix, "env-prefix", "", "honor PREFIX_CACHE_DIR, PREFIX_HOME etc., used with -app")
+}
+
+// locator returns d, or the application's ProjectDirs if -app was given.
+func (f *appFlags) locator(d dirs.Dirs) (dirs.Locator, error) {
+	if f.app == "" {
+		return d, nil
+	}
+	var opts []dirs.ProjectOption
+	if f.envPrefix != "" {
+		opts = append(opts, dirs.WithEnvPrefix(f.envPrefix))
+	}
+	return dirs.ProjectDirsFrom(d, f.qualifier, f.org, f.app, opts...)
+}
+
+func run(d dirs.Dirs, args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "doctor" {
+		return runDoctor(d, args[1:], stdout, stderr)
+	}
+
+	flags := flag.NewFlagSet("dirs", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "plain", "output format: plain, json, env or a shell name")
+	prefix := flags.String("prefix", "", "prefix for variable names in env and shell output")
+	var af appFlags
+	af.register(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: dirs [flags] [kind...]")
+		fmt.Fprintln(stderr, "       dirs doctor [flags] [kind...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	l, err := af.locator(d)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	kinds, all, err := parseKinds(flags.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	var results []result
+	failed := false
+	for _, k := range kinds {
+		path, err := l.Dir(k)
+		// When listing everything, leave out directories that do not exist on
+		// this platform rather than reporting them as failures.
+		if all && errors.Is(err, dirs.ErrNotSupported) {
+			continue
+		}
+		if err != nil && !all {
+			failed = true
+		}
+		results = append(results, result{kind: k, path: path, err: err})
+	}
+
+	switch *format {
+	case "plain":
+		err = writePlain(stdout, stderr, results, all)
+	case "json":
+		err = writeJSON(stdout, results)
+	case "env":
+		err = writeEnv(stdout, stderr, results, *prefix)
+	default:
+		sh, parseErr := dirs.ParseShell(*format)
+		if parseErr != nil {
+			fmt.Fprintf(stderr, "dirs: unknown format %q\n", *format)
+			return 2
+		}
+		if len(flags.Args()) > 0 {
+			fmt.Fprintln(stderr, "dirs: shell output always includes every directory")
+			return 2
+		}
+		err = dirs.WriteExports(stdout, l, sh, *prefix)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// parseKinds returns the kinds named in args, or every kind if args is empty.
+func parseKinds(args []string) (kinds []dirs.Kind, all bool, err error) {
+	if len(args) == 0 {
+		return dirs.Kinds(), true, nil
+	}
+	for _, arg := range args {
+		k, err := dirs.ParseKind(arg)
+		if err != nil {
+			return nil, false, err
+		}
+		kinds = append(kinds, k)
+	}
+	return kinds, false, nil
+}
+
+// writePlain prints a single path on its own, or a table of kinds and paths.
+// Errors go to stderr so that `$(dirs cache)` captures only the path.
+func writePlain(stdout, stderr io.Writer, results []result, all bool) error {
+	if len(results) == 1 && !all {
+		if results[0].err != nil {
+			fmt.Fprintln(stderr, results[0].err)
+			return nil
+		}
+		_, err := fmt.Fprintln(stdout, results[0].path)
+		return err
+	}
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(w, "%s\t(%v)\n", r.kind, r.err)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\n", r.kind, r.path)
+	}
+	return w.Flush()
+}
+
+type jsonEntry struct {
+	Path string `json:"path,omitempty"`
+	Err  string `json:"error,omitempty"`
+}
+
+// writeJSON prints an object keyed by kind name, in the same shape as an
+// encoded dirs.Snapshot.
+func writeJSON(stdout io.Writer, results []result) error {
+	m := make(map[dirs.Kind]jsonEntry, len(results))
+	for _, r := range results {
+		e := jsonEntry{Path: r.path}
+		if r.err != nil {
+			e.Err = r.err.Error()
+		}
+		m[r.kind] = e
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// writeEnv prints KEY=value lines, e.g. CONFIG_DIR=/home/me/.config.
+// Directories that could not be resolved are reported on stderr.
+func writeEnv(stdout, stderr io.Writer, results []result, prefix string) error {
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintln(stderr, r.err)
+			continue
+		}
+		if _, err := fmt.Fprintf(stdout, "%s%s=%s\n", prefix, r.kind.EnvName(), r.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runDoctor(d dirs.Dirs, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("dirs doctor", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "plain", "output format: plain or json")
+	var af appFlags
+	af.register(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: dirs doctor [flags] [kind...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	l, err := af.locator(d)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	kinds, all, err := parseKinds(flags.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if all && af.app != "" {
+		// Applications only have the base directories.
+		kinds = kinds[:0]
+		for _, k := range dirs.Kinds() {
+			if _, err := l.Dir(k); !errors.Is(err, dirs.ErrNotSupported) {
+				kinds = append(kinds, k)
+			}
+		}
+	}
+	findings := dirs.Check(l, kinds...)
+
+	switch *format {
+	case "plain":
+		for _, f := range findings {
+			fmt.Fprintln(stdout, f)
+		}
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	default:
+		fmt.Fprintf(stderr, "dirs: unknown format %q\n", *format)
+		return 2
+	}
+	if dirs.MaxSeverity(findings) == dirs.SeverityError {
+		return 1
+	}
+	return 0
+}
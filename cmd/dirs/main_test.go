@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ansrivas/dirs"
+)
+
+func newTestDirs(t *testing.T) dirs.Dirs {
+	t.Helper()
+	d, err := dirs.NewDirsFor(dirs.Linux, dirs.MapEnv(map[string]string{
+		"HOME":            "/home/me",
+		"XDG_CONFIG_HOME": "/etc/me",
+		"MYAPP_CACHE_DIR": "/var/cache/myapp",
+	}))
+	if err != nil {
+		t.Fatalf("NewDirsFor returned error: %v", err)
+	}
+	return d
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		expected string
+	}{
+		{"single", []string{"config"}, 0, "/etc/me\n"},
+		{"method name", []string{"CacheDir"}, 0, "/home/me/.cache\n"},
+		{"app", []string{"-app", "MyApp", "config"}, 0, "/etc/me/myapp\n"},
+		{"app env override", []string{"-app", "MyApp", "-env-prefix", "MYAPP", "cache"}, 0, "/var/cache/myapp\n"},
+		{"env", []string{"-format", "env", "config", "data_local"}, 0, "CONFIG_DIR=/etc/me\nDATA_LOCAL_DIR=/home/me/.local/share\n"},
+		{"env prefix", []string{"-format", "env", "-prefix", "MYAPP_", "cache"}, 0, "MYAPP_CACHE_DIR=/home/me/.cache\n"},
+		{"shell with kinds", []string{"-format", "bash", "cache"}, 2, ""},
+		{"table", []string{"home", "config"}, 0, "home    /home/me\nconfig  /etc/me\n"},
+		{"unset", []string{"runtime"}, 1, ""},
+		{"unknown kind", []string{"temp"}, 2, ""},
+		{"unknown format", []string{"-format", "yaml"}, 2, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(newTestDirs(t), tt.args, &stdout, &stderr)
+			if code != tt.code {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.code, code, stderr.String())
+			}
+			if stdout.String() != tt.expected {
+				t.Errorf("Expected output %q, got %q", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunAll(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(newTestDirs(t), []string{"-format", "json"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+
+	var snapshot dirs.Snapshot
+	if err := json.Unmarshal(stdout.Bytes(), &snapshot); err != nil {
+		t.Fatalf("Output is not a snapshot: %v", err)
+	}
+	if path := snapshot.Path(dirs.Font); path != "/home/me/.local/share/fonts" {
+		t.Errorf("Expected font dir in output, got %s", path)
+	}
+	if snapshot.Err(dirs.Runtime) == "" {
+		t.Error("Expected the unset runtime dir to be reported")
+	}
+
+	stdout.Reset()
+	if code := run(newTestDirs(t), []string{"-app", "MyApp"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	if strings.Contains(stdout.String(), "audio") || !strings.Contains(stdout.String(), "/home/me/.local/state/myapp") {
+		t.Errorf("Expected only application directories, got:\n%s", stdout.String())
+	}
+}
+
+func TestRunShell(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(newTestDirs(t), []string{"-format", "fish", "-app", "MyApp"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "set -gx CONFIG_DIR '/etc/me/myapp'\n") {
+		t.Errorf("Expected a fish export of the config dir, got:\n%s", stdout.String())
+	}
+}
+
+func TestRunDoctor(t *testing.T) {
+	home := t.TempDir()
+	d, err := dirs.NewDirsFor(dirs.Linux, dirs.MapEnv(map[string]string{"HOME": home}))
+	if err != nil {
+		t.Fatalf("NewDirsFor returned error: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run(d, []string{"doctor", "home", "cache"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	expected := "ok: home: " + home + ": ok\ninfo: cache: " + home + "/.cache: does not exist yet\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run(d, []string{"doctor", "-format", "json", "-app", "MyApp"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	var findings []struct {
+		Kind     string `json:"kind"`
+		Severity string `json:"severity"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &findings); err != nil {
+		t.Fatalf("Output is not JSON: %v", err)
+	}
+	if len(findings) != 7 || findings[0].Kind != "cache" {
+		t.Errorf("Expected findings for the 7 application directories, got %+v", findings)
+	}
+}
+
+func TestRunDoctorFailure(t *testing.T) {
+	d, err := dirs.NewDirsFor(dirs.Linux, dirs.MapEnv(nil))
+	if err != nil {
+		t.Fatalf("NewDirsFor returned error: %v", err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := run(d, []string{"doctor", "config"}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 without a home directory, got %d", code)
+	}
+	if !strings.HasPrefix(stdout.String(), "error: config:") {
+		t.Errorf("Expected an error finding, got %q", stdout.String())
+	}
+}
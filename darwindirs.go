@@ -0,0 +1,95 @@
+package dirs
+
+import (
+	"path"
+)
+
+type darwinDirs struct {
+	*options
+}
+
+func (d *darwinDirs) resolve(kind Kind) (Resolution, error) {
+	switch kind {
+	case Home:
+		return d.resolveHome("HOME")
+	case Cache:
+		return d.getUserHomeSubDir(kind, "Library", "Caches")
+	case Config, Data:
+		// Corresponds to Application Support directory on macOS
+		return d.getUserHomeSubDir(kind, "Library", "Application Support")
+	case DataLocal:
+		// macOS doesn't typically distinguish between roaming and local data in the same way Windows does.
+		// Use the standard Application Support directory.
+		res := Resolution{Kind: kind}
+		data, err := d.resolve(Data)
+		if err != nil {
+			return res, err
+		}
+		res.derive(SourceDerived, Data.String(), data.Path, data)
+		return res, nil
+	case Preference:
+		return d.getUserHomeSubDir(kind, "Library", "Preferences")
+	case Executable, Runtime, Template:
+		// No standard equivalent specified in the reference doc for macOS.
+		return d.unsupported(kind)
+	case State:
+		// No standard equivalent specified in the reference doc for macOS.
+		// Often, state might go into Application Support or Caches depending on volatility.
+		return d.unsupported(kind)
+	case Audio:
+		return d.getUserHomeSubDir(kind, "Music")
+	case Desktop:
+		return d.getUserHomeSubDir(kind, "Desktop")
+	case Document:
+		return d.getUserHomeSubDir(kind, "Documents")
+	case Download:
+		return d.getUserHomeSubDir(kind, "Downloads")
+	case Font:
+		return d.getUserHomeSubDir(kind, "Library", "Fonts")
+	case Picture:
+		return d.getUserHomeSubDir(kind, "Pictures")
+	case Public:
+		return d.getUserHomeSubDir(kind, "Public")
+	case Video:
+		// Note: macOS standard is "Movies", not "Videos"
+		return d.getUserHomeSubDir(kind, "Movies")
+	}
+	return d.unsupported(kind)
+}
+
+// Helper for standard user directories under $HOME
+func (d *darwinDirs) getUserHomeSubDir(kind Kind, subPath ...string) (Resolution, error) {
+	res := Resolution{Kind: kind}
+	home, err := d.resolve(Home)
+	if err != nil {
+		return res, err
+	}
+	detail := path.Join(append([]string{"$HOME"}, subPath...)...)
+	res.derive(SourceDefault, detail, d.join(append([]string{home.Path}, subPath...)...), home)
+	return res, nil
+}
+
+func (d *darwinDirs) systemConfigDirs() ([]string, error) {
+	// The local domain counterpart of ~/Library/Application Support.
+	return []string{"/Library/Application Support"}, nil
+}
+
+func (d *darwinDirs) systemDataDirs() ([]string, error) {
+	return []string{"/Library/Application Support"}, nil
+}
+
+func (d *darwinDirs) platform() Platform {
+	return Darwin
+}
+
+func (d *darwinDirs) projectPath(qualifier, organization, application string) string {
+	return macProjectPath(qualifier, organization, application)
+}
+
+func (d *darwinDirs) join(elem ...string) string {
+	return path.Join(elem...)
+}
+
+func (d *darwinDirs) isAbs(dir string) bool {
+	return path.IsAbs(dir)
+}
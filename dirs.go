@@ -1,7 +1,24 @@
 package dirs
 
+import "fmt"
+
 // Dirs defines methods to retrieve platform-specific directories.
 type Dirs interface {
+	// Dir returns the directory of the given kind. The named methods below
+	// are shorthands for it, e.g. CacheDir is Dir(Cache).
+	Dir(kind Kind) (string, error)
+	// Resolve is like Dir but also explains where the directory came from
+	// and which candidates were skipped. On error the Resolution still lists
+	// the candidates that were tried.
+	Resolve(kind Kind) (Resolution, error)
+	// EnsureDir is like Dir but also creates the directory, and any missing
+	// parents, if it does not exist. Base directories such as Config and
+	// Runtime are created with mode 0700 and the user's folders with 0755;
+	// an existing directory with a more permissive mode is tightened. The
+	// mode of the home directory is never changed. Dirs returned by
+	// NewDirsFor describe another system and report ErrNotSupported.
+	EnsureDir(kind Kind) (string, error)
+
 	HomeDir() (string, error)
 	CacheDir() (string, error)
 	ConfigDir() (string, error)
@@ -20,4 +37,77 @@ type Dirs interface {
 	PublicDir() (string, error)
 	TemplateDir() (string, error)
 	VideoDir() (string, error)
+
+	// SystemConfigDirs returns the system-wide configuration directories, in
+	// order of decreasing precedence.
+	SystemConfigDirs() ([]string, error)
+	// SystemDataDirs returns the system-wide data directories, in order of
+	// decreasing precedence.
+	SystemDataDirs() ([]string, error)
+}
+
+// Locator is the part of Dirs that is also implemented by ProjectDirs, for
+// functions that work with either.
+type Locator interface {
+	Dir(kind Kind) (string, error)
 }
+
+// NewDirs returns the Dirs for the current platform, reading the process
+// environment.
+func NewDirs() Dirs {
+	return New()
+}
+
+// New returns the Dirs for the current platform configured by opts.
+func New(opts ...Option) Dirs {
+	d, err := newLayoutDirs(currentPlatform, newOptions(opts))
+	if err != nil {
+		// currentPlatform is always one of the supported platforms.
+		panic(err)
+	}
+	return d
+}
+
+// layoutDirs implements Dirs on top of a layout, which only needs to resolve
+// directories by kind.
+type layoutDirs struct {
+	layout
+}
+
+func (d *layoutDirs) Dir(kind Kind) (string, error) {
+	res, err := d.Resolve(kind)
+	return res.Path, err
+}
+
+func (d *layoutDirs) Resolve(kind Kind) (Resolution, error) {
+	if !kind.valid() {
+		return Resolution{Kind: kind}, &DirError{Kind: kind, Err: fmt.Errorf("unknown directory kind")}
+	}
+	return d.resolve(kind)
+}
+
+func (d *layoutDirs) EnsureDir(kind Kind) (string, error) {
+	return ensureDir(d, kind)
+}
+
+func (d *layoutDirs) HomeDir() (string, error)       { return d.Dir(Home) }
+func (d *layoutDirs) CacheDir() (string, error)      { return d.Dir(Cache) }
+func (d *layoutDirs) ConfigDir() (string, error)     { return d.Dir(Config) }
+func (d *layoutDirs) DataDir() (string, error)       { return d.Dir(Data) }
+func (d *layoutDirs) DataLocalDir() (string, error)  { return d.Dir(DataLocal) }
+func (d *layoutDirs) ExecutableDir() (string, error) { return d.Dir(Executable) }
+func (d *layoutDirs) PreferenceDir() (string, error) { return d.Dir(Preference) }
+func (d *layoutDirs) RuntimeDir() (string, error)    { return d.Dir(Runtime) }
+func (d *layoutDirs) StateDir() (string, error)      { return d.Dir(State) }
+func (d *layoutDirs) AudioDir() (string, error)      { return d.Dir(Audio) }
+func (d *layoutDirs) DesktopDir() (string, error)    { return d.Dir(Desktop) }
+func (d *layoutDirs) DocumentDir() (string, error)   { return d.Dir(Document) }
+func (d *layoutDirs) DownloadDir() (string, error)   { return d.Dir(Download) }
+func (d *layoutDirs) FontDir() (string, error)       { return d.Dir(Font) }
+func (d *layoutDirs) PictureDir() (string, error)    { return d.Dir(Picture) }
+func (d *layoutDirs) PublicDir() (string, error)     { return d.Dir(Public) }
+func (d *layoutDirs) TemplateDir() (string, error)   { return d.Dir(Template) }
+func (d *layoutDirs) VideoDir() (string, error)      { return d.Dir(Video) }
+
+func (d *layoutDirs) SystemConfigDirs() ([]string, error) { return d.systemConfigDirs() }
+func (d *layoutDirs) SystemDataDirs() ([]string, error)   { return d.systemDataDirs() }
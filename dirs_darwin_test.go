@@ -3,6 +3,7 @@
 package dirs
 
 import (
+	"errors"
 	"path/filepath"
 	"testing"
 )
@@ -22,11 +23,11 @@ func checkPath(t *testing.T, name string, path string, err error) {
 	}
 }
 
-// Helper to check if a path is empty and the error is nil
-func checkEmptyPath(t *testing.T, name string, path string, err error) {
+// Helper to check that a directory is reported as unsupported
+func checkUnsupported(t *testing.T, name string, path string, err error) {
 	t.Helper()
-	if err != nil {
-		t.Errorf("%s() returned an error: %v", name, err)
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("%s() expected ErrNotSupported, got: %v", name, err)
 	}
 	if path != "" {
 		t.Errorf("%s() returned a non-empty path (%s), expected empty", name, path)
@@ -103,22 +104,22 @@ func TestDarwinDirs(t *testing.T) {
 		}
 	})
 
-	// Dirs expected to be empty on macOS
+	// Dirs without a macOS equivalent
 	t.Run("ExecutableDir", func(t *testing.T) {
 		path, err := d.ExecutableDir()
-		checkEmptyPath(t, "ExecutableDir", path, err)
+		checkUnsupported(t, "ExecutableDir", path, err)
 	})
 	t.Run("RuntimeDir", func(t *testing.T) {
 		path, err := d.RuntimeDir()
-		checkEmptyPath(t, "RuntimeDir", path, err)
+		checkUnsupported(t, "RuntimeDir", path, err)
 	})
 	t.Run("StateDir", func(t *testing.T) {
 		path, err := d.StateDir()
-		checkEmptyPath(t, "StateDir", path, err)
+		checkUnsupported(t, "StateDir", path, err)
 	})
 	t.Run("TemplateDir", func(t *testing.T) {
 		path, err := d.TemplateDir()
-		checkEmptyPath(t, "TemplateDir", path, err)
+		checkUnsupported(t, "TemplateDir", path, err)
 	})
 
 	// User Dirs - check they are under HOME
@@ -145,3 +146,31 @@ func TestDarwinDirs(t *testing.T) {
 		})
 	}
 }
+
+func TestDarwinSystemDirs(t *testing.T) {
+	d := NewDirs()
+	expected := "/Library/Application Support"
+
+	for name, getter := range map[string]func() ([]string, error){
+		"SystemConfigDirs": d.SystemConfigDirs,
+		"SystemDataDirs":   d.SystemDataDirs,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dirs, err := getter()
+			if err != nil {
+				t.Fatalf("%s() returned an error: %v", name, err)
+			}
+			if len(dirs) != 1 || dirs[0] != expected {
+				t.Errorf("%s expected [%s], got %v", name, expected, dirs)
+			}
+		})
+	}
+}
+
+func TestDarwinEmptyUnsupported(t *testing.T) {
+	d := New(WithEmptyUnsupported())
+	path, err := d.RuntimeDir()
+	if err != nil || path != "" {
+		t.Errorf("Expected (\"\", nil) with WithEmptyUnsupported, got (%s, %v)", path, err)
+	}
+}
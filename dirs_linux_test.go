@@ -3,9 +3,13 @@
 package dirs
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 	"testing"
 )
 
@@ -16,13 +20,10 @@ func checkPath(t *testing.T, name string, path string, err error) {
 		t.Errorf("%s() returned an error: %v", name, err)
 	}
 	if path == "" {
-		// Allow empty paths for specific cases like RuntimeDir if env var is not set
-		if name != "RuntimeDir" || os.Getenv("XDG_RUNTIME_DIR") != "" {
-			t.Errorf("%s() returned an empty path", name)
-		}
+		t.Errorf("%s() returned an empty path", name)
 	}
-	// Basic sanity check: ensure paths are absolute unless it's an intentionally empty result
-	if path != "" && !filepath.IsAbs(path) {
+	// Basic sanity check: ensure paths are absolute
+	if !filepath.IsAbs(path) {
 		t.Errorf("%s() returned a non-absolute path: %s", name, path)
 	}
 }
@@ -99,9 +100,15 @@ func TestLinuxDirs(t *testing.T) {
 	})
 
 	t.Run("RuntimeDir", func(t *testing.T) {
-		// This might be empty if XDG_RUNTIME_DIR is not set, which is valid.
+		// There is no fallback if XDG_RUNTIME_DIR is not set.
 		path, err := d.RuntimeDir()
-		checkPath(t, "RuntimeDir", path, err) // checkPath allows empty for RuntimeDir
+		if os.Getenv("XDG_RUNTIME_DIR") == "" {
+			if !errors.Is(err, ErrNotSet) {
+				t.Errorf("RuntimeDir() expected ErrNotSet, got: %v", err)
+			}
+			return
+		}
+		checkPath(t, "RuntimeDir", path, err)
 	})
 
 	t.Run("StateDir", func(t *testing.T) {
@@ -128,11 +135,16 @@ func TestLinuxDirs(t *testing.T) {
 		"VideoDir":    {d.VideoDir, "XDG_VIDEOS_DIR", "Videos"},
 	}
 
+	// Defaults only apply when neither the env var nor user-dirs.dirs is set.
+	configDir, _ := d.ConfigDir()
+	_, statErr := os.Stat(filepath.Join(configDir, "user-dirs.dirs"))
+	hasUserDirsFile := statErr == nil
+
 	for name, data := range userDirs {
 		t.Run(name, func(t *testing.T) {
 			path, err := data.getter()
 			checkPath(t, name, path, err)
-			if os.Getenv(data.envVar) == "" && !strings.HasSuffix(path, data.defaultSuffix) {
+			if os.Getenv(data.envVar) == "" && !hasUserDirsFile && !strings.HasSuffix(path, data.defaultSuffix) {
 				t.Errorf("Default %s path should end with %s, got: %s", name, data.defaultSuffix, path)
 			}
 		})
@@ -187,6 +199,12 @@ func TestLinuxXdgOverrides(t *testing.T) {
 		t.Run(tt.name+" Override", func(t *testing.T) {
 			expectedPath := filepath.Join(testDir, tt.name+"_override")
 			t.Setenv(tt.envVar, expectedPath)
+			if tt.envVar == "XDG_RUNTIME_DIR" {
+				// The runtime dir is validated, so it must exist with mode 0700.
+				if err := os.Mkdir(expectedPath, 0o700); err != nil {
+					t.Fatal(err)
+				}
+			}
 
 			path, err := tt.getter()
 			if err != nil {
@@ -230,3 +248,701 @@ func TestLinuxXdgOverrides(t *testing.T) {
 		}
 	})
 }
+
+func TestLinuxUserDirsFile(t *testing.T) {
+	d := NewDirs()
+	home := t.TempDir()
+	configDir := filepath.Join(home, "config")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+
+	if err := os.MkdirAll(configDir, 0o700); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	contents := "XDG_DOWNLOAD_DIR=\"$HOME/Téléchargements\"\nXDG_MUSIC_DIR=\"/srv/music\"\n"
+	if err := os.WriteFile(filepath.Join(configDir, "user-dirs.dirs"), []byte(contents), 0o600); err != nil {
+		t.Fatalf("Failed to write user-dirs.dirs: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		envVar   string
+		getter   func() (string, error)
+		expected string
+	}{
+		{"DownloadDir", "XDG_DOWNLOAD_DIR", d.DownloadDir, filepath.Join(home, "Téléchargements")},
+		{"AudioDir", "XDG_MUSIC_DIR", d.AudioDir, "/srv/music"},
+		{"PictureDir", "XDG_PICTURES_DIR", d.PictureDir, filepath.Join(home, "Pictures")}, // not in file, default
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.envVar, "")
+			path, err := tt.getter()
+			checkPath(t, tt.name, path, err)
+			if path != tt.expected {
+				t.Errorf("%s expected '%s', got '%s'", tt.name, tt.expected, path)
+			}
+		})
+	}
+
+	t.Run("EnvOverridesFile", func(t *testing.T) {
+		override := filepath.Join(home, "env-downloads")
+		t.Setenv("XDG_DOWNLOAD_DIR", override)
+		path, err := d.DownloadDir()
+		checkPath(t, "DownloadDir", path, err)
+		if path != override {
+			t.Errorf("DownloadDir expected '%s' from env, got '%s'", override, path)
+		}
+	})
+}
+
+func TestLinuxSystemDirs(t *testing.T) {
+	d := NewDirs()
+
+	tests := []struct {
+		name     string
+		envVar   string
+		getter   func() ([]string, error)
+		value    string
+		expected []string
+	}{
+		{"ConfigDefault", "XDG_CONFIG_DIRS", d.SystemConfigDirs, "", []string{"/etc/xdg"}},
+		{"ConfigOverride", "XDG_CONFIG_DIRS", d.SystemConfigDirs, "/opt/xdg:/etc/xdg", []string{"/opt/xdg", "/etc/xdg"}},
+		{"ConfigRelativeIgnored", "XDG_CONFIG_DIRS", d.SystemConfigDirs, "relative::/opt/xdg", []string{"/opt/xdg"}},
+		{"ConfigOnlyRelative", "XDG_CONFIG_DIRS", d.SystemConfigDirs, "relative:other", []string{"/etc/xdg"}},
+		{"DataDefault", "XDG_DATA_DIRS", d.SystemDataDirs, "", []string{"/usr/local/share", "/usr/share"}},
+		{"DataOverride", "XDG_DATA_DIRS", d.SystemDataDirs, "/opt/share", []string{"/opt/share"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.envVar, tt.value)
+			dirs, err := tt.getter()
+			if err != nil {
+				t.Fatalf("%s returned error: %v", tt.name, err)
+			}
+			if strings.Join(dirs, ":") != strings.Join(tt.expected, ":") {
+				t.Errorf("Expected %v, got %v", tt.expected, dirs)
+			}
+		})
+	}
+}
+
+func TestLinuxFindFiles(t *testing.T) {
+	d := NewDirs()
+	root := t.TempDir()
+	userConfig := filepath.Join(root, "config")
+	systemConfig := []string{filepath.Join(root, "etc1"), filepath.Join(root, "etc2")}
+	userData := filepath.Join(root, "data")
+	systemData := filepath.Join(root, "share")
+	t.Setenv("XDG_CONFIG_HOME", userConfig)
+	t.Setenv("XDG_CONFIG_DIRS", strings.Join(systemConfig, ":"))
+	t.Setenv("XDG_DATA_HOME", userData)
+	t.Setenv("XDG_DATA_DIRS", systemData)
+
+	writeFile := func(dir, name string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		return path
+	}
+
+	name := filepath.Join("myapp", "config.toml")
+	second := writeFile(systemConfig[1], name)
+
+	t.Run("FindConfigFile system only", func(t *testing.T) {
+		path, err := FindConfigFile(d, name)
+		if err != nil {
+			t.Fatalf("FindConfigFile returned error: %v", err)
+		}
+		if path != second {
+			t.Errorf("Expected %s, got %s", second, path)
+		}
+	})
+
+	first := writeFile(userConfig, name)
+
+	t.Run("FindConfigFile user wins", func(t *testing.T) {
+		path, err := FindConfigFile(d, name)
+		if err != nil {
+			t.Fatalf("FindConfigFile returned error: %v", err)
+		}
+		if path != first {
+			t.Errorf("Expected %s, got %s", first, path)
+		}
+	})
+
+	t.Run("FindConfigFiles", func(t *testing.T) {
+		paths, err := FindConfigFiles(d, name)
+		if err != nil {
+			t.Fatalf("FindConfigFiles returned error: %v", err)
+		}
+		if strings.Join(paths, ":") != first+":"+second {
+			t.Errorf("Expected [%s %s], got %v", first, second, paths)
+		}
+	})
+
+	t.Run("FindDataFile", func(t *testing.T) {
+		expected := writeFile(systemData, filepath.Join("myapp", "icon.png"))
+		path, err := FindDataFile(d, filepath.Join("myapp", "icon.png"))
+		if err != nil {
+			t.Fatalf("FindDataFile returned error: %v", err)
+		}
+		if path != expected {
+			t.Errorf("Expected %s, got %s", expected, path)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := FindDataFile(d, "missing")
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected fs.ErrNotExist, got %v", err)
+		}
+		paths, err := FindConfigFiles(d, "missing")
+		if err != nil || len(paths) != 0 {
+			t.Errorf("Expected no paths and no error, got %v, %v", paths, err)
+		}
+	})
+}
+
+func TestLinuxInjectedEnv(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	d := New(WithEnv(MapEnv(map[string]string{
+		"HOME":            home,
+		"XDG_CONFIG_HOME": "/custom/config",
+		"XDG_CONFIG_DIRS": "/custom/xdg",
+	})))
+
+	tests := []struct {
+		name     string
+		getter   func() (string, error)
+		expected string
+	}{
+		{"HomeDir", d.HomeDir, home},
+		{"CacheDir", d.CacheDir, filepath.Join(home, ".cache")},
+		{"ConfigDir", d.ConfigDir, "/custom/config"},
+		{"StateDir", d.StateDir, filepath.Join(home, ".local", "state")},
+		{"AudioDir", d.AudioDir, filepath.Join(home, "Music")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := tt.getter()
+			checkPath(t, tt.name, path, err)
+			if path != tt.expected {
+				t.Errorf("%s expected '%s', got '%s'", tt.name, tt.expected, path)
+			}
+		})
+	}
+
+	t.Run("RuntimeDir", func(t *testing.T) {
+		path, err := d.RuntimeDir()
+		var dirErr *DirError
+		if !errors.As(err, &dirErr) || dirErr.Kind != Runtime || !errors.Is(err, ErrNotSet) {
+			t.Errorf("Expected a RuntimeDir DirError wrapping ErrNotSet, got (%s, %v)", path, err)
+		}
+	})
+
+	t.Run("RuntimeDirEmptyUnsupported", func(t *testing.T) {
+		d := New(WithEnv(MapEnv(map[string]string{"HOME": home})), WithEmptyUnsupported())
+		path, err := d.RuntimeDir()
+		if err != nil || path != "" {
+			t.Errorf("Expected (\"\", nil) with WithEmptyUnsupported, got (%s, %v)", path, err)
+		}
+	})
+
+	t.Run("HomeNotFound", func(t *testing.T) {
+		d := New(WithEnv(MapEnv(nil)))
+		if _, err := d.CacheDir(); !errors.Is(err, ErrHomeNotFound) {
+			t.Errorf("Expected ErrHomeNotFound, got %v", err)
+		}
+	})
+
+	t.Run("SystemConfigDirs", func(t *testing.T) {
+		dirs, err := d.SystemConfigDirs()
+		if err != nil || len(dirs) != 1 || dirs[0] != "/custom/xdg" {
+			t.Errorf("Expected [/custom/xdg], got (%v, %v)", dirs, err)
+		}
+	})
+
+	t.Run("HomeResolver", func(t *testing.T) {
+		d := New(WithEnv(MapEnv(nil)), WithHomeDir(func() (string, error) { return "/resolved", nil }))
+		path, err := d.DataDir()
+		if err != nil || path != "/resolved/.local/share" {
+			t.Errorf("Expected /resolved/.local/share, got (%s, %v)", path, err)
+		}
+	})
+}
+
+func TestLinuxRuntimeDir(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	valid := filepath.Join(t.TempDir(), "runtime")
+	if err := os.Mkdir(valid, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	open := filepath.Join(t.TempDir(), "open")
+	if err := os.Mkdir(open, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(open, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		d := New(WithEnv(MapEnv(map[string]string{"HOME": home, "XDG_RUNTIME_DIR": valid})))
+		path, err := d.RuntimeDir()
+		if err != nil || path != valid {
+			t.Errorf("Expected %s, got (%s, %v)", valid, path, err)
+		}
+	})
+
+	for name, dir := range map[string]string{"Missing": filepath.Join(home, "missing"), "Permissive": open} {
+		t.Run(name, func(t *testing.T) {
+			d := New(WithEnv(MapEnv(map[string]string{"HOME": home, "XDG_RUNTIME_DIR": dir})))
+			res, err := d.Resolve(Runtime)
+			if !errors.Is(err, ErrNotSet) {
+				t.Fatalf("Expected ErrNotSet for %s, got (%s, %v)", dir, res.Path, err)
+			}
+			if n := len(res.Skipped); n == 0 || res.Skipped[n-1].Value != dir {
+				t.Errorf("Expected %s to be recorded as skipped, got %v", dir, res.Skipped)
+			}
+		})
+	}
+
+	t.Run("Insecure", func(t *testing.T) {
+		d := New(WithEnv(MapEnv(map[string]string{"HOME": home, "XDG_RUNTIME_DIR": open})))
+		if _, err := d.RuntimeDir(); !errors.Is(err, ErrInsecure) || !errors.Is(err, ErrNotSet) {
+			t.Errorf("Expected an error wrapping ErrInsecure and ErrNotSet, got %v", err)
+		}
+	})
+
+	t.Run("NewDirsForSkipsValidation", func(t *testing.T) {
+		d, err := NewDirsFor(Linux, MapEnv(map[string]string{"HOME": "/home/u", "XDG_RUNTIME_DIR": "/run/user/1000"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path, err := d.RuntimeDir(); err != nil || path != "/run/user/1000" {
+			t.Errorf("Expected /run/user/1000, got (%s, %v)", path, err)
+		}
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		tmp := t.TempDir()
+		d := New(WithEnv(MapEnv(map[string]string{"HOME": home, "TMPDIR": tmp, "XDG_RUNTIME_DIR": open})), WithRuntimeFallback())
+		res, err := d.Resolve(Runtime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := filepath.Join(tmp, "dirs-runtime-"+strconv.Itoa(os.Getuid()))
+		if res.Path != expected || res.Source != SourceFallback {
+			t.Errorf("Expected fallback %s, got %v", expected, res)
+		}
+		fi, err := os.Stat(expected)
+		if err != nil || fi.Mode().Perm() != 0o700 {
+			t.Errorf("Expected fallback created with mode 0700, got (%v, %v)", fi, err)
+		}
+
+		// A second resolution reuses the directory.
+		if path, err := d.RuntimeDir(); err != nil || path != expected {
+			t.Errorf("Expected %s again, got (%s, %v)", expected, path, err)
+		}
+
+		var info bool
+		for _, f := range Check(d, Runtime) {
+			info = info || (f.Severity == SeverityInfo && strings.Contains(f.Message, "fallback"))
+		}
+		if !info {
+			t.Errorf("Expected Check to report the fallback, got %v", Check(d, Runtime))
+		}
+	})
+
+	t.Run("FallbackTampered", func(t *testing.T) {
+		tmp := t.TempDir()
+		if err := os.Mkdir(filepath.Join(tmp, "dirs-runtime-"+strconv.Itoa(os.Getuid())), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(filepath.Join(tmp, "dirs-runtime-"+strconv.Itoa(os.Getuid())), 0o777); err != nil {
+			t.Fatal(err)
+		}
+		d := New(WithEnv(MapEnv(map[string]string{"HOME": home, "TMPDIR": tmp})), WithRuntimeFallback())
+		if _, err := d.RuntimeDir(); !errors.Is(err, ErrInsecure) {
+			t.Errorf("Expected ErrInsecure for a world-writable fallback, got %v", err)
+		}
+	})
+}
+
+// withPasswdFile makes the home directory fallback read file instead of
+// /etc/passwd.
+func withPasswdFile(file string) Option {
+	return func(o *options) {
+		o.passwdPath = file
+	}
+}
+
+func TestLinuxPasswdHome(t *testing.T) {
+	passwd := filepath.Join(t.TempDir(), "passwd")
+	uid := os.Getuid()
+	content := "# comment\n" +
+		"other:x:" + strconv.Itoa(uid+1) + ":100::/home/other:/bin/sh\n" +
+		"me:x:" + strconv.Itoa(uid) + ":100:Me:/home/me:/bin/sh\n"
+	if err := os.WriteFile(passwd, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Fallback", func(t *testing.T) {
+		// The fallback only applies to the process environment.
+		t.Setenv("HOME", "")
+		d := New(withPasswdFile(passwd))
+		res, err := d.Resolve(Home)
+		if err != nil || res.Path != "/home/me" || res.Source != SourceFile {
+			t.Fatalf("Expected /home/me from the passwd file, got (%v, %v)", res, err)
+		}
+		if res.Detail != passwd+":3" {
+			t.Errorf("Expected the passwd line as detail, got %s", res.Detail)
+		}
+		if len(res.Skipped) == 0 || res.Skipped[0].Detail != "HOME" {
+			t.Errorf("Expected HOME to be recorded as skipped, got %v", res.Skipped)
+		}
+		if path, err := d.CacheDir(); err != nil || path != "/home/me/.cache" {
+			t.Errorf("Expected /home/me/.cache, got (%s, %v)", path, err)
+		}
+	})
+
+	t.Run("HomeSet", func(t *testing.T) {
+		d := New(WithEnv(MapEnv(map[string]string{"HOME": "/env/home"})), withPasswdFile(passwd))
+		if path, err := d.HomeDir(); err != nil || path != "/env/home" {
+			t.Errorf("Expected $HOME to take precedence, got (%s, %v)", path, err)
+		}
+	})
+
+	t.Run("InjectedEnv", func(t *testing.T) {
+		d := New(WithEnv(MapEnv(nil)), withPasswdFile(passwd))
+		if _, err := d.HomeDir(); !errors.Is(err, ErrHomeNotFound) {
+			t.Errorf("Expected an injected environment not to consult the passwd file, got %v", err)
+		}
+	})
+
+	t.Run("NewDirsFor", func(t *testing.T) {
+		d, err := NewDirsFor(Linux, nil, withPasswdFile(passwd))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.HomeDir(); !errors.Is(err, ErrHomeNotFound) {
+			t.Errorf("Expected NewDirsFor not to consult the local passwd file, got %v", err)
+		}
+	})
+}
+
+func TestLinuxForUser(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/caller/config")
+
+	home := t.TempDir()
+	passwd := filepath.Join(t.TempDir(), "passwd")
+	content := "alice:x:1500:1500::" + home + ":/bin/sh\n" +
+		"nohome:x:1501:1501:::/bin/sh\n"
+	if err := os.WriteFile(passwd, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".config"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	userDirs := "XDG_MUSIC_DIR=\"$HOME/Tunes\"\n"
+	if err := os.WriteFile(filepath.Join(home, ".config", "user-dirs.dirs"), []byte(userDirs), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, newDirs := range map[string]func() (Dirs, error){
+		"ForUser": func() (Dirs, error) { return ForUser("alice", withPasswdFile(passwd)) },
+		"ForUID":  func() (Dirs, error) { return ForUID(1500, withPasswdFile(passwd)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			d, err := newDirs()
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := d.Resolve(Home)
+			if err != nil || res.Path != home || res.Detail != passwd+":1" {
+				t.Errorf("Expected %s from %s:1, got (%v, %v)", home, passwd, res, err)
+			}
+			if path, err := d.ConfigDir(); err != nil || path != filepath.Join(home, ".config") {
+				t.Errorf("Expected the caller's XDG_CONFIG_HOME to be ignored, got (%s, %v)", path, err)
+			}
+			if path, err := d.AudioDir(); err != nil || path != filepath.Join(home, "Tunes") {
+				t.Errorf("Expected the user's user-dirs.dirs to be read, got (%s, %v)", path, err)
+			}
+		})
+	}
+
+	t.Run("TargetEnv", func(t *testing.T) {
+		d, err := ForUser("alice", withPasswdFile(passwd), WithEnv(MapEnv(map[string]string{"XDG_CACHE_HOME": "/var/cache/alice"})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path, err := d.CacheDir(); err != nil || path != "/var/cache/alice" {
+			t.Errorf("Expected the given environment to be used, got (%s, %v)", path, err)
+		}
+	})
+
+	t.Run("Check", func(t *testing.T) {
+		d, err := ForUser("alice", withPasswdFile(passwd))
+		if err != nil {
+			t.Fatal(err)
+		}
+		findings := Check(d, Config)
+		if len(findings) != 1 || !strings.Contains(findings[0].Message, "not alice (uid 1500)") {
+			t.Errorf("Expected .config to be reported as not owned by alice, got %v", findings)
+		}
+		if os.Geteuid() != 0 {
+			return
+		}
+		if err := os.Chown(filepath.Join(home, ".config"), 1500, 1500); err != nil {
+			t.Fatal(err)
+		}
+		if findings := Check(d, Config); len(findings) != 1 || findings[0].Severity != SeverityOK {
+			t.Errorf("Expected alice's .config to be ok, got %v", findings)
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		if _, err := ForUser("bob", withPasswdFile(passwd)); !errors.Is(err, ErrUnknownUser) {
+			t.Errorf("Expected ErrUnknownUser, got %v", err)
+		}
+		if _, err := ForUID(1501, withPasswdFile(passwd)); !errors.Is(err, ErrHomeNotFound) {
+			t.Errorf("Expected ErrHomeNotFound for an entry without home, got %v", err)
+		}
+	})
+}
+
+func TestLinuxForUserSymlink(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root to act on behalf of another user")
+	}
+
+	home := t.TempDir()
+	victim := t.TempDir()
+	passwd := filepath.Join(t.TempDir(), "passwd")
+	if err := os.WriteFile(passwd, []byte("alice:x:1500:1500::"+home+":/bin/sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chown(home, 1500, 1500); err != nil {
+		t.Fatal(err)
+	}
+	// The user redirects .config to a directory of root's.
+	config := filepath.Join(home, ".config")
+	if err := os.Symlink(victim, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Lchown(config, 1500, 1500); err != nil {
+		t.Fatal(err)
+	}
+	d, err := ForUser("alice", withPasswdFile(passwd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := ProjectDirsFrom(d, "", "", "ourapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.EnsureDir(Config); !errors.Is(err, ErrInsecure) {
+		t.Errorf("Expected ErrInsecure for a symlink owned by the user, got %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(victim, "ourapp")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected nothing to be created through the symlink, got %v", err)
+	}
+
+	t.Run("OwnedByRoot", func(t *testing.T) {
+		// A symlink set up by the administrator is followed.
+		data := filepath.Join(home, ".local")
+		if err := os.Symlink(victim, data); err != nil {
+			t.Fatal(err)
+		}
+		dir, err := p.EnsureDir(Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fi, err := os.Stat(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uid, _ := fileUID(fi); uid != 1500 || !strings.HasPrefix(dir, data) {
+			t.Errorf("Expected %s to be created below %s and owned by uid 1500, got uid %d", dir, victim, uid)
+		}
+	})
+}
+
+func TestLinuxMigrateForUser(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root to act on behalf of another user")
+	}
+
+	home := t.TempDir()
+	passwd := filepath.Join(t.TempDir(), "passwd")
+	if err := os.WriteFile(passwd, []byte("alice:x:1500:1500::"+home+":/bin/sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	legacy := filepath.Join(home, ".ourapp")
+	if err := os.MkdirAll(filepath.Join(legacy, "themes"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacy, "themes", "dark.toml"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	d, err := ForUser("alice", withPasswdFile(passwd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := ProjectDirsFrom(d, "", "", "ourapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps, err := Migrate(p, Migration{Legacy: legacy, Mapping: map[string]Kind{"themes/dark.toml": Config}})
+	if err != nil || len(steps) != 1 {
+		t.Fatalf("Expected one step, got (%v, %v)", steps, err)
+	}
+	themes := filepath.Dir(steps[0].To)
+	fi, err := os.Stat(themes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uid, _ := fileUID(fi); uid != 1500 {
+		t.Errorf("Expected %s to be owned by uid 1500, got %d", themes, uid)
+	}
+}
+
+func TestLinuxForInvokingUser(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root to act on behalf of another user")
+	}
+
+	home := t.TempDir()
+	passwd := filepath.Join(t.TempDir(), "passwd")
+	if err := os.WriteFile(passwd, []byte("alice:x:1500:1600::"+home+":/bin/sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	env := MapEnv(map[string]string{"HOME": "/root", "SUDO_UID": "1500", "SUDO_USER": "alice"})
+	d, err := ForInvokingUser(WithEnv(env), withPasswdFile(passwd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path, err := d.HomeDir(); err != nil || path != home {
+		t.Fatalf("Expected the invoking user's home %s, got (%s, %v)", home, path, err)
+	}
+
+	p, err := ProjectDirsFrom(d, "com", "Org", "MyApp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := p.EnsureDir(Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{filepath.Join(home, ".config"), dir} {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uid, _ := fileUID(fi); uid != 1500 {
+			t.Errorf("Expected %s to be owned by uid 1500, got %d", path, uid)
+		}
+	}
+
+	file := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Chown(p, file); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := fi.Sys().(*syscall.Stat_t); st.Uid != 1500 || st.Gid != 1600 {
+		t.Errorf("Expected %s to be owned by 1500:1600, got %d:%d", file, st.Uid, st.Gid)
+	}
+
+	t.Run("ProcessEnv", func(t *testing.T) {
+		t.Setenv("SUDO_UID", "1500")
+		t.Setenv("XDG_CONFIG_HOME", "/root/.config")
+		d, err := ForInvokingUser(withPasswdFile(passwd))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path, err := d.ConfigDir(); err != nil || path != filepath.Join(home, ".config") {
+			t.Errorf("Expected the process's XDG_CONFIG_HOME to be ignored, got (%s, %v)", path, err)
+		}
+	})
+
+	t.Run("NotEscalated", func(t *testing.T) {
+		d, err := ForInvokingUser(WithEnv(MapEnv(map[string]string{"HOME": "/root"})), withPasswdFile(passwd))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path, _ := d.HomeDir(); path != "/root" {
+			t.Errorf("Expected the current user's home, got %s", path)
+		}
+		if err := Chown(d, file); err != nil {
+			t.Errorf("Expected Chown to do nothing for the current user, got %v", err)
+		}
+	})
+}
+
+func TestLinuxSecureMode(t *testing.T) {
+	passwd := filepath.Join(t.TempDir(), "passwd")
+	content := "me:x:" + strconv.Itoa(os.Geteuid()) + ":0::/home/trusted:/bin/sh\n" +
+		"victim:x:" + strconv.Itoa(os.Geteuid()+1) + ":0::/home/victim:/bin/sh\n"
+	if err := os.WriteFile(passwd, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	env := MapEnv(map[string]string{
+		"HOME":            "/home/attacker",
+		"XDG_CONFIG_HOME": "/home/attacker/config",
+		"XDG_RUNTIME_DIR": t.TempDir(),
+		"SUDO_UID":        strconv.Itoa(os.Geteuid() + 1),
+	})
+
+	d := New(WithEnv(env), WithSecureMode(), withPasswdFile(passwd))
+	res, err := d.Resolve(Home)
+	if err != nil || res.Path != "/home/trusted" || res.Source != SourceFile {
+		t.Errorf("Expected the home from the passwd entry, got (%v, %v)", res, err)
+	}
+	if path, err := d.ConfigDir(); err != nil || path != "/home/trusted/.config" {
+		t.Errorf("Expected XDG_CONFIG_HOME to be ignored, got (%s, %v)", path, err)
+	}
+	if _, err := d.RuntimeDir(); !errors.Is(err, ErrNotSet) {
+		t.Errorf("Expected XDG_RUNTIME_DIR to be ignored, got %v", err)
+	}
+
+	t.Run("HomeDirOption", func(t *testing.T) {
+		d := New(WithEnv(env), WithSecureMode(), WithHomeDir(func() (string, error) { return "/opt/svc", nil }))
+		if path, err := d.HomeDir(); err != nil || path != "/opt/svc" {
+			t.Errorf("Expected WithHomeDir to apply in secure mode, got (%s, %v)", path, err)
+		}
+	})
+
+	t.Run("NoPasswdEntry", func(t *testing.T) {
+		d := New(WithEnv(env), WithSecureMode(), withPasswdFile(filepath.Join(t.TempDir(), "missing")))
+		if _, err := d.CacheDir(); !errors.Is(err, ErrHomeNotFound) {
+			t.Errorf("Expected ErrHomeNotFound rather than $HOME, got %v", err)
+		}
+	})
+
+	t.Run("InvokingUser", func(t *testing.T) {
+		d, err := ForInvokingUser(WithEnv(env), WithSecureMode(), withPasswdFile(passwd))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path, err := d.HomeDir(); err != nil || path != "/home/trusted" {
+			t.Errorf("Expected SUDO_UID to be ignored in secure mode, got (%s, %v)", path, err)
+		}
+	})
+}
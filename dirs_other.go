@@ -0,0 +1,6 @@
+//go:build !linux && !darwin && !windows
+
+package dirs
+
+// The other Unix systems follow the XDG Base Directory spec, as Linux does.
+const currentPlatform = Linux
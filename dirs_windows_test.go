@@ -3,6 +3,7 @@
 package dirs
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -24,11 +25,11 @@ func checkPath(t *testing.T, name string, path string, err error) {
 	}
 }
 
-// Helper to check if a path is empty and the error is nil
-func checkEmptyPath(t *testing.T, name string, path string, err error) {
+// Helper to check that a directory is reported as unsupported
+func checkUnsupported(t *testing.T, name string, path string, err error) {
 	t.Helper()
-	if err != nil {
-		t.Errorf("%s() returned an error: %v", name, err)
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("%s() expected ErrNotSupported, got: %v", name, err)
 	}
 	if path != "" {
 		t.Errorf("%s() returned a non-empty path (%s), expected empty", name, path)
@@ -94,22 +95,22 @@ func TestWindowsDirs(t *testing.T) {
 		}
 	})
 
-	// Dirs expected to be empty on Windows
+	// Dirs without a Windows equivalent
 	t.Run("ExecutableDir", func(t *testing.T) {
 		path, err := d.ExecutableDir()
-		checkEmptyPath(t, "ExecutableDir", path, err)
+		checkUnsupported(t, "ExecutableDir", path, err)
 	})
 	t.Run("RuntimeDir", func(t *testing.T) {
 		path, err := d.RuntimeDir()
-		checkEmptyPath(t, "RuntimeDir", path, err)
+		checkUnsupported(t, "RuntimeDir", path, err)
 	})
 	t.Run("StateDir", func(t *testing.T) {
 		path, err := d.StateDir()
-		checkEmptyPath(t, "StateDir", path, err)
+		checkUnsupported(t, "StateDir", path, err)
 	})
 	t.Run("FontDir", func(t *testing.T) {
 		path, err := d.FontDir()
-		checkEmptyPath(t, "FontDir", path, err)
+		checkUnsupported(t, "FontDir", path, err)
 	})
 
 	// User Dirs - check they are under USERPROFILE or PUBLIC
@@ -152,3 +153,26 @@ func TestWindowsDirs(t *testing.T) {
 		}
 	})
 }
+
+func TestWindowsSystemDirs(t *testing.T) {
+	d := NewDirs()
+	programData := os.Getenv("PROGRAMDATA")
+	if programData == "" {
+		t.Skip("Skipping because PROGRAMDATA is not set.")
+	}
+
+	for name, getter := range map[string]func() ([]string, error){
+		"SystemConfigDirs": d.SystemConfigDirs,
+		"SystemDataDirs":   d.SystemDataDirs,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dirs, err := getter()
+			if err != nil {
+				t.Fatalf("%s() returned an error: %v", name, err)
+			}
+			if len(dirs) != 1 || !strings.EqualFold(dirs[0], programData) {
+				t.Errorf("%s expected [%s], got %v", name, programData, dirs)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package dirs
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// dirMode returns the mode EnsureDir creates a directory of the given kind
+// with. The XDG Base Directory spec requires base directories to be created
+// with mode 0700, and the same applies to the equivalent directories on the
+// other platforms; the home directory and the user's own folders, such as
+// Music, are shared conventionally with mode 0755.
+func dirMode(kind Kind) fs.FileMode {
+	if kind != Home && isBaseKind(kind) {
+		return 0o700
+	}
+	return 0o755
+}
+
+// ensureDir implements EnsureDir for any Locator, except that it defers to
+// SystemDirs.EnsureDir for system directories. A directory that the
+// platform does not provide, reported as an empty path with
+// WithEmptyUnsupported, is returned as is without creating anything. For
+// another user's directories, from ForUser or ForInvokingUser, the
+// directories it creates are owned by that user.
+func ensureDir(l Locator, kind Kind) (string, error) {
+	if s, ok := l.(*SystemDirs); ok {
+		return s.EnsureDir(kind)
+	}
+	dir, err := l.Dir(kind)
+	if err != nil || dir == "" {
+		return dir, err
+	}
+	if isPure(l) {
+		return "", &DirError{Kind: kind, Err: fmt.Errorf("%w: %s is computed for another system", ErrNotSupported, dir)}
+	}
+	mode := dirMode(kind)
+	owner := ownerOf(l)
+	if err := mkdirAllOwned(dir, mode, owner); err != nil {
+		return "", &DirError{Kind: kind, Err: err}
+	}
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return "", &DirError{Kind: kind, Err: err}
+	}
+	if !fi.IsDir() {
+		return "", &DirError{Kind: kind, Err: fmt.Errorf("%s exists but is not a directory", dir)}
+	}
+	// The home directory belongs to the user, not to this package, so its
+	// mode is left alone, also when a preset such as PresetDotfile resolves
+	// a base directory to it.
+	if kind == Home || isHomeDir(l, dir) {
+		return dir, nil
+	}
+	// MkdirAll leaves existing directories alone, so one created earlier
+	// with a looser mode, e.g. by os.MkdirAll(dir, 0755), is tightened here.
+	uid := os.Getuid()
+	if owner != nil {
+		uid = owner.uid
+	}
+	if err := repairMode(dir, fi, mode, uid); err != nil {
+		return "", &DirError{Kind: kind, Err: err}
+	}
+	return dir, nil
+}
+
+// isPure reports whether l was computed by NewDirsFor rather than for the
+// running system.
+func isPure(l Locator) bool {
+	switch l := l.(type) {
+	case *layoutDirs:
+		return l.isPure()
+	case *ProjectDirs:
+		return isPure(l.base)
+	}
+	return false
+}
+
+// isHomeDir reports whether dir is the home directory of l.
+func isHomeDir(l Locator, dir string) bool {
+	home, err := l.Dir(Home)
+	return err == nil && home != "" && filepath.Clean(home) == filepath.Clean(dir)
+}
@@ -0,0 +1,156 @@
+package dirs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestEnsureDir(t *testing.T) {
+	root := t.TempDir()
+	paths := map[Kind]string{
+		Config: filepath.Join(root, "config", "nested"),
+		Audio:  filepath.Join(root, "audio"),
+		Cache:  filepath.Join(root, "cache"),
+		Data:   filepath.Join(root, "file"),
+	}
+	l := locatorFunc(func(kind Kind) (string, error) {
+		if kind == Runtime {
+			return "", &DirError{Kind: kind, Err: ErrNotSet}
+		}
+		return paths[kind], nil
+	})
+	if err := os.Mkdir(paths[Cache], 0o700); err != nil {
+		t.Fatal(err)
+	}
+	// Set the mode explicitly, as Mkdir is subject to the umask.
+	if err := os.Chmod(paths[Cache], 0o775); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(paths[Data], nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		kind Kind
+		mode os.FileMode
+	}{
+		{Config, 0o700},
+		{Audio, 0o755},
+		{Cache, 0o700}, // tightened
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind.String(), func(t *testing.T) {
+			path, err := ensureDir(l, tt.kind)
+			if err != nil || path != paths[tt.kind] {
+				t.Fatalf("Expected %s, got (%s, %v)", paths[tt.kind], path, err)
+			}
+			fi, err := os.Stat(path)
+			if err != nil || !fi.IsDir() {
+				t.Fatalf("Expected a directory at %s, got (%v, %v)", path, fi, err)
+			}
+			// Creation modes are subject to the umask, which can only
+			// remove bits, and Windows does not report Unix modes.
+			if runtime.GOOS != "windows" && fi.Mode().Perm()&^tt.mode != 0 {
+				t.Errorf("Expected at most mode %04o, got %04o", tt.mode, fi.Mode().Perm())
+			}
+		})
+	}
+
+	t.Run("Home", func(t *testing.T) {
+		home := filepath.Join(root, "home")
+		if err := os.Mkdir(home, 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(home, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		// As with PresetDotfile, where the base directories are the home
+		// directory.
+		l := locatorFunc(func(kind Kind) (string, error) { return home, nil })
+		for _, kind := range []Kind{Home, Config} {
+			if _, err := ensureDir(l, kind); err != nil {
+				t.Fatal(err)
+			}
+			fi, err := os.Stat(home)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if runtime.GOOS != "windows" && fi.Mode().Perm() != 0o755 {
+				t.Errorf("EnsureDir(%s) changed the home directory's mode to %04o", kind, fi.Mode().Perm())
+			}
+		}
+	})
+
+	t.Run("NotADirectory", func(t *testing.T) {
+		if _, err := ensureDir(l, Data); err == nil {
+			t.Error("Expected an error for a file in the way")
+		}
+	})
+
+	t.Run("Unresolved", func(t *testing.T) {
+		if _, err := ensureDir(l, Runtime); !errors.Is(err, ErrNotSet) {
+			t.Errorf("Expected ErrNotSet, got %v", err)
+		}
+	})
+}
+
+func TestEnsureDirPure(t *testing.T) {
+	// Run in an empty directory, where a relative path would be created.
+	t.Chdir(t.TempDir())
+	root := filepath.Join(t.TempDir(), "home")
+	d, err := NewDirsFor(Windows, MapEnv(map[string]string{"USERPROFILE": `C:\Users\me`}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.EnsureDir(Config); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+	if entries, err := os.ReadDir("."); err != nil || len(entries) != 0 {
+		t.Errorf("Expected nothing to be created, got (%v, %v)", entries, err)
+	}
+
+	d, err = NewDirsFor(currentPlatform, MapEnv(map[string]string{"HOME": root, "USERPROFILE": root}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := ProjectDirsFrom(d, "com", "Org", "MyApp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := filepath.Join(t.TempDir(), ".myapp")
+	if err := os.Mkdir(legacy, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacy, "config.toml"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Migrate(p, Migration{Legacy: legacy, Mapping: map[string]Kind{"config.toml": Config}}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected Migrate to report ErrNotSupported, got %v", err)
+	}
+	if _, err := os.Stat(root); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected %s not to be created, got %v", root, err)
+	}
+}
+
+func TestProjectDirsEnsureDir(t *testing.T) {
+	home := t.TempDir()
+	d := New(WithHomeDir(func() (string, error) { return home, nil }), WithEnv(MapEnv(nil)))
+	p, err := ProjectDirsFrom(d, "com", "Org", "MyApp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := p.CacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := p.EnsureDir(Cache)
+	if err != nil || path != expected {
+		t.Fatalf("Expected %s, got (%s, %v)", expected, path, err)
+	}
+	if fi, err := os.Stat(path); err != nil || !fi.IsDir() {
+		t.Errorf("Expected %s to be created, got %v", path, err)
+	}
+}
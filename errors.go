@@ -0,0 +1,85 @@
+package dirs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotSupported is returned for directories that have no equivalent on
+	// the platform, such as RuntimeDir on macOS.
+	ErrNotSupported = errors.New("directory not supported on this platform")
+
+	// ErrNotSet is returned for directories that are only defined by an
+	// environment variable which is not set, such as XDG_RUNTIME_DIR.
+	ErrNotSet = errors.New("directory not set in the environment")
+
+	// ErrHomeNotFound is returned when the home directory, on which most other
+	// directories depend, cannot be determined.
+	ErrHomeNotFound = errors.New("home directory not found")
+
+	// ErrInsecure is returned for directories whose ownership or permissions
+	// would let other users read or tamper with their contents.
+	ErrInsecure = errors.New("directory has unsafe ownership or permissions")
+
+	// ErrUnknownUser is returned by ForUser and ForUID when the passwd
+	// database has no such user.
+	ErrUnknownUser = errors.New("unknown user")
+)
+
+// DirError records why a directory could not be resolved.
+type DirError struct {
+	Kind Kind  // the directory that could not be resolved
+	Err  error // the reason, typically wrapping one of the Err* values
+}
+
+func (e *DirError) Error() string {
+	return "dirs: " + e.Kind.String() + ": " + e.Err.Error()
+}
+
+func (e *DirError) Unwrap() error {
+	return e.Err
+}
+
+// WithEmptyUnsupported restores the behavior of earlier versions, where
+// unsupported and unset directories were reported as an empty path with a
+// nil error instead of ErrNotSupported or ErrNotSet.
+//
+// Callers must then check for empty paths themselves: joining an empty path
+// yields a relative path that resolves against the working directory.
+func WithEmptyUnsupported() Option {
+	return func(o *options) {
+		o.emptyUnsupported = true
+	}
+}
+
+// unsupported reports that kind has no equivalent on the platform.
+func (o *options) unsupported(kind Kind) (Resolution, error) {
+	res := Resolution{Kind: kind}
+	if o.emptyUnsupported {
+		return res, nil
+	}
+	return res, &DirError{Kind: kind, Err: ErrNotSupported}
+}
+
+// notSet reports that kind is only available through envVar, which is unset
+// or unusable. res holds the candidates tried so far, including envVar.
+func (o *options) notSet(res Resolution, envVar string) (Resolution, error) {
+	if o.emptyUnsupported {
+		return res, nil
+	}
+	reason := "is not set"
+	if n := len(res.Skipped); n > 0 && res.Skipped[n-1].Detail == envVar && res.Skipped[n-1].Value != "" {
+		reason = "is unusable: " + res.Skipped[n-1].Reason
+	}
+	return res, &DirError{Kind: res.Kind, Err: fmt.Errorf("%w: $%s %s", ErrNotSet, envVar, reason)}
+}
+
+// unusable is like notSet for an envVar that is set but failed validation
+// with cause. The error wraps both ErrNotSet and cause, e.g. ErrInsecure.
+func (o *options) unusable(res Resolution, envVar string, cause error) (Resolution, error) {
+	if o.emptyUnsupported {
+		return res, nil
+	}
+	return res, &DirError{Kind: res.Kind, Err: fmt.Errorf("%w: $%s is unusable: %w", ErrNotSet, envVar, cause)}
+}
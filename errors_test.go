@@ -0,0 +1,36 @@
+package dirs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDirError(t *testing.T) {
+	err := error(&DirError{Kind: State, Err: ErrNotSupported})
+
+	if !errors.Is(err, ErrNotSupported) {
+		t.Error("Expected DirError to unwrap to ErrNotSupported")
+	}
+	var dirErr *DirError
+	if !errors.As(err, &dirErr) || dirErr.Kind != State {
+		t.Errorf("Expected errors.As to find the state DirError, got %v", dirErr)
+	}
+	if expected := "dirs: state: directory not supported on this platform"; err.Error() != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, err.Error())
+	}
+}
+
+func TestHomeNotFound(t *testing.T) {
+	t.Parallel()
+
+	errResolver := errors.New("resolver failed")
+	d := New(WithHomeDir(func() (string, error) { return "", errResolver }))
+
+	_, err := d.HomeDir()
+	if !errors.Is(err, ErrHomeNotFound) {
+		t.Errorf("Expected ErrHomeNotFound, got %v", err)
+	}
+	if !errors.Is(err, errResolver) {
+		t.Errorf("Expected the resolver's error to be preserved, got %v", err)
+	}
+}
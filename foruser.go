@@ -0,0 +1,60 @@
+package dirs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ForUser returns the Linux directories of the user named username, for
+// programs such as provisioning daemons that run as root and manage other
+// users' files. The home directory is read from the passwd database, and
+// the caller's own environment is ignored: unless opts include WithEnv with
+// the target user's environment, every directory takes its XDG default
+// below that home, and the user directories are read from the target user's
+// user-dirs.dirs. The runtime directory is validated against the target
+// user's uid.
+//
+// Directories created with EnsureDir are owned by the user, which requires
+// root; Chown does the same for the program's own files. ForUser returns an
+// error wrapping ErrUnknownUser if there is no such user, and
+// ErrNotSupported on platforms other than Linux.
+func ForUser(username string, opts ...Option) (Dirs, error) {
+	return forUser("user "+strconv.Quote(username), func(e passwdEntry) bool { return e.name == username }, opts)
+}
+
+// ForUID is like ForUser but identifies the user by uid.
+func ForUID(uid int, opts ...Option) (Dirs, error) {
+	return forUser("uid "+strconv.Itoa(uid), func(e passwdEntry) bool { return e.uid == uid }, opts)
+}
+
+func forUser(desc string, match func(passwdEntry) bool, opts []Option) (Dirs, error) {
+	if currentPlatform != Linux {
+		return nil, fmt.Errorf("dirs: %s: %w", desc, ErrNotSupported)
+	}
+	o := newOptions(append([]Option{WithEnv(MapEnv(nil))}, opts...))
+	entry, err := lookupPasswd(o.passwdFile(), match)
+	if errors.Is(err, errNoPasswdEntry) {
+		err = ErrUnknownUser
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dirs: %s: %w", desc, err)
+	}
+	if !strings.HasPrefix(entry.home, "/") {
+		return nil, fmt.Errorf("dirs: %s: %w: passwd entry has home %q", desc, ErrHomeNotFound, entry.home)
+	}
+	o.user = &entry
+	return newLayoutDirs(Linux, o)
+}
+
+// uid returns the uid of the user whose directories are resolved: the user
+// selected by ForUser, or else the effective user, who owns the files the
+// process creates.
+func (o *options) uid() int {
+	if o.user != nil {
+		return o.user.uid
+	}
+	return os.Geteuid()
+}
@@ -0,0 +1,111 @@
+package dirs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind identifies one of the directories provided by Dirs.
+type Kind int
+
+// The directory kinds, one for each of the named Dirs methods.
+const (
+	Home       Kind = iota + 1 // HomeDir
+	Cache                      // CacheDir
+	Config                     // ConfigDir
+	Data                       // DataDir
+	DataLocal                  // DataLocalDir
+	Executable                 // ExecutableDir
+	Preference                 // PreferenceDir
+	Runtime                    // RuntimeDir
+	State                      // StateDir
+	Audio                      // AudioDir
+	Desktop                    // DesktopDir
+	Document                   // DocumentDir
+	Download                   // DownloadDir
+	Font                       // FontDir
+	Picture                    // PictureDir
+	Public                     // PublicDir
+	Template                   // TemplateDir
+	Video                      // VideoDir
+)
+
+var kindNames = [...]string{
+	Home:       "home",
+	Cache:      "cache",
+	Config:     "config",
+	Data:       "data",
+	DataLocal:  "data_local",
+	Executable: "executable",
+	Preference: "preference",
+	Runtime:    "runtime",
+	State:      "state",
+	Audio:      "audio",
+	Desktop:    "desktop",
+	Document:   "document",
+	Download:   "download",
+	Font:       "font",
+	Picture:    "picture",
+	Public:     "public",
+	Template:   "template",
+	Video:      "video",
+}
+
+// Kinds returns every directory kind, in the order the Dirs methods are
+// declared.
+func Kinds() []Kind {
+	kinds := make([]Kind, 0, len(kindNames)-1)
+	for k := Home; k <= Video; k++ {
+		kinds = append(kinds, k)
+	}
+	return kinds
+}
+
+// String returns the name of the kind, e.g. "cache" or "data_local".
+func (k Kind) String() string {
+	if !k.valid() {
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+	return kindNames[k]
+}
+
+// ParseKind returns the kind named s. It accepts the names returned by
+// String as well as the Dirs method names, ignoring case, hyphens and
+// underscores, so "data_local", "data-local" and "DataLocalDir" are all
+// DataLocal.
+func ParseKind(s string) (Kind, error) {
+	name := normalizeKindName(s)
+	for _, k := range Kinds() {
+		if normalizeKindName(k.String()) == name {
+			return k, nil
+		}
+	}
+	return 0, fmt.Errorf("dirs: unknown directory kind %q", s)
+}
+
+func normalizeKindName(s string) string {
+	s = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(s))
+	return strings.TrimSuffix(s, "dir")
+}
+
+func (k Kind) valid() bool {
+	return k >= Home && k <= Video
+}
+
+// MarshalText implements encoding.TextMarshaler using the kind's name.
+func (k Kind) MarshalText() ([]byte, error) {
+	if !k.valid() {
+		return nil, fmt.Errorf("dirs: invalid directory kind %d", int(k))
+	}
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseKind.
+func (k *Kind) UnmarshalText(text []byte) error {
+	parsed, err := ParseKind(string(text))
+	if err != nil {
+		return err
+	}
+	*k = parsed
+	return nil
+}
@@ -0,0 +1,85 @@
+package dirs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestKinds(t *testing.T) {
+	kinds := Kinds()
+	if len(kinds) != 18 {
+		t.Fatalf("Expected 18 kinds, got %d", len(kinds))
+	}
+	if kinds[0] != Home || kinds[len(kinds)-1] != Video {
+		t.Errorf("Expected kinds from Home to Video, got %v", kinds)
+	}
+
+	for _, k := range kinds {
+		parsed, err := ParseKind(k.String())
+		if err != nil {
+			t.Errorf("ParseKind(%q) returned error: %v", k.String(), err)
+		}
+		if parsed != k {
+			t.Errorf("ParseKind(%q) expected %v, got %v", k.String(), k, parsed)
+		}
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	for _, s := range []string{"data_local", "data-local", "DataLocal", "DataLocalDir", "DATA_LOCAL"} {
+		k, err := ParseKind(s)
+		if err != nil || k != DataLocal {
+			t.Errorf("ParseKind(%q) expected DataLocal, got (%v, %v)", s, k, err)
+		}
+	}
+	if _, err := ParseKind("temp"); err == nil {
+		t.Error("Expected an error for an unknown kind")
+	}
+	if s := Kind(0).String(); s != "Kind(0)" {
+		t.Errorf("Expected Kind(0), got %s", s)
+	}
+}
+
+func TestDirMatchesMethods(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewDirsFor(Linux, MapEnv(map[string]string{"HOME": "/home/me"}))
+	if err != nil {
+		t.Fatalf("NewDirsFor returned error: %v", err)
+	}
+	methods := []func() (string, error){
+		d.HomeDir, d.CacheDir, d.ConfigDir, d.DataDir, d.DataLocalDir, d.ExecutableDir,
+		d.PreferenceDir, d.RuntimeDir, d.StateDir, d.AudioDir, d.DesktopDir, d.DocumentDir,
+		d.DownloadDir, d.FontDir, d.PictureDir, d.PublicDir, d.TemplateDir, d.VideoDir,
+	}
+	for i, k := range Kinds() {
+		want, wantErr := methods[i]()
+		got, err := d.Dir(k)
+		if got != want || (err == nil) != (wantErr == nil) {
+			t.Errorf("Dir(%v) = (%s, %v), method returned (%s, %v)", k, got, err, want, wantErr)
+		}
+	}
+
+	if _, err := d.Dir(Kind(99)); err == nil {
+		t.Error("Expected an error for an invalid kind")
+	}
+}
+
+func TestProjectDirsDir(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewDirsFor(Linux, MapEnv(map[string]string{"HOME": "/home/me"}))
+	if err != nil {
+		t.Fatalf("NewDirsFor returned error: %v", err)
+	}
+	p, err := ProjectDirsFrom(d, "com", "Org", "MyApp")
+	if err != nil {
+		t.Fatalf("ProjectDirsFrom returned error: %v", err)
+	}
+	if path, err := p.Dir(State); err != nil || path != "/home/me/.local/state/myapp" {
+		t.Errorf("Dir(State) expected /home/me/.local/state/myapp, got (%s, %v)", path, err)
+	}
+	if _, err := p.Dir(Audio); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported for a user directory, got %v", err)
+	}
+}
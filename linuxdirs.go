@@ -0,0 +1,281 @@
+package dirs
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+type linuxDirs struct {
+	*options
+
+	// host is the native layout when the XDG rules are applied on another
+	// platform by PresetXDG. It then provides the home directory and path
+	// syntax.
+	host layout
+}
+
+func (d *linuxDirs) resolve(kind Kind) (Resolution, error) {
+	switch kind {
+	case Home:
+		if d.host != nil {
+			return d.host.resolve(Home)
+		}
+		return d.getHomeDir()
+	case Cache:
+		return d.getBaseDir(kind, "XDG_CACHE_HOME", ".cache")
+	case Config:
+		return d.getBaseDir(kind, "XDG_CONFIG_HOME", ".config")
+	case Data:
+		return d.getBaseDir(kind, "XDG_DATA_HOME", ".local/share")
+	case DataLocal:
+		// XDG Base Directory Spec suggests local data is the same as data.
+		return d.getDerivedDir(kind, Data)
+	case Executable:
+		// Fallback based on XDG spec recommendation: $HOME/.local/bin
+		return d.getBaseDir(kind, "XDG_BIN_HOME", ".local/bin")
+	case Preference:
+		// Preferences are typically stored in the config directory on Linux.
+		return d.getDerivedDir(kind, Config)
+	case Runtime:
+		return d.getRuntimeDir()
+	case State:
+		return d.getBaseDir(kind, "XDG_STATE_HOME", ".local/state")
+	case Audio:
+		return d.getUserDir(kind, "XDG_MUSIC_DIR", "Music")
+	case Desktop:
+		return d.getUserDir(kind, "XDG_DESKTOP_DIR", "Desktop")
+	case Document:
+		return d.getUserDir(kind, "XDG_DOCUMENTS_DIR", "Documents")
+	case Download:
+		return d.getUserDir(kind, "XDG_DOWNLOAD_DIR", "Downloads")
+	case Font:
+		// Fonts are typically placed in the data directory according to XDG spec.
+		return d.getDerivedDir(kind, Data, "fonts")
+	case Picture:
+		return d.getUserDir(kind, "XDG_PICTURES_DIR", "Pictures")
+	case Public:
+		return d.getUserDir(kind, "XDG_PUBLICSHARE_DIR", "Public")
+	case Template:
+		return d.getUserDir(kind, "XDG_TEMPLATES_DIR", "Templates")
+	case Video:
+		return d.getUserDir(kind, "XDG_VIDEOS_DIR", "Videos")
+	}
+	return d.unsupported(kind)
+}
+
+// lookupEnvDir records the XDG environment variable envVar in res if it is
+// set to an absolute path. The spec requires relative paths to be treated
+// as invalid and ignored; they are recorded as skipped candidates so that
+// the rejection shows up in Resolve and Check.
+func (d *linuxDirs) lookupEnvDir(res *Resolution, envVar string) bool {
+	dir := d.getenv(envVar)
+	switch {
+	case dir == "":
+		res.skip(SourceEnv, envVar, "", "not set")
+		return false
+	case !d.isAbs(dir):
+		res.skip(SourceEnv, envVar, dir, "relative path ignored as required by the XDG spec")
+		return false
+	}
+	res.use(SourceEnv, envVar, dir)
+	return true
+}
+
+// getHomeDir resolves the home directory of the user selected by ForUser, or
+// else $HOME, falling back to the current user's entry in the passwd
+// database when it is unset. The fallback is reported by Resolve with
+// SourceFile. It is only used with the process environment: an environment
+// given with WithEnv or NewDirsFor, like WithHomeDir, is authoritative. In
+// secure mode only the passwd entry is used.
+func (d *linuxDirs) getHomeDir() (Resolution, error) {
+	if d.user != nil && d.home == nil {
+		res := Resolution{Kind: Home}
+		res.use(SourceFile, fmt.Sprintf("%s:%d", d.passwdFile(), d.user.line), d.user.home)
+		return res, nil
+	}
+	if d.secure && d.home == nil {
+		res := Resolution{Kind: Home}
+		if d.lookupPasswdHome(&res, d.uid()) {
+			return res, nil
+		}
+		return res, &DirError{Kind: Home, Err: fmt.Errorf("%w: no usable passwd entry in secure mode", ErrHomeNotFound)}
+	}
+	res, err := d.resolveHome("HOME")
+	if err == nil || d.home != nil || d.env != nil {
+		return res, err
+	}
+	if d.lookupPasswdHome(&res, d.uid()) {
+		return res, nil
+	}
+	return res, err
+}
+
+// getRuntimeDir validates XDG_RUNTIME_DIR. The spec provides no fallback for
+// the runtime dir; it is only available when the session manager sets
+// XDG_RUNTIME_DIR, unless WithRuntimeFallback is used.
+func (d *linuxDirs) getRuntimeDir() (Resolution, error) {
+	const envVar = "XDG_RUNTIME_DIR"
+	res := Resolution{Kind: Runtime}
+	var invalid error
+	if d.lookupEnvDir(&res, envVar) {
+		if d.pure {
+			return res, nil
+		}
+		if invalid = validateRuntimeDir(res.Path, d.uid()); invalid == nil {
+			return res, nil
+		}
+		rejected := res.Path
+		res.use(SourceNone, "", "")
+		res.skip(SourceEnv, envVar, rejected, invalid.Error())
+	}
+	switch {
+	case !d.runtimeFallback && invalid != nil:
+		return d.unusable(res, envVar, invalid)
+	case !d.runtimeFallback:
+		return d.notSet(res, envVar)
+	}
+
+	tmpDir := d.getenv("TMPDIR")
+	if !path.IsAbs(tmpDir) {
+		tmpDir = "/tmp"
+	}
+	dir, err := runtimeFallback(tmpDir, d.uid())
+	if err != nil {
+		return res, &DirError{Kind: Runtime, Err: fmt.Errorf("fallback: %w", err)}
+	}
+	res.use(SourceFallback, "$TMPDIR/dirs-runtime-<uid>", dir)
+	return res, nil
+}
+
+// getBaseDir checks the XDG environment variable and falls back to a path
+// relative to the home directory.
+func (d *linuxDirs) getBaseDir(kind Kind, envVar, defaultSubPath string) (Resolution, error) {
+	res := Resolution{Kind: kind}
+	if d.lookupEnvDir(&res, envVar) {
+		return res, nil
+	}
+	return d.getHomeSubDir(res, defaultSubPath)
+}
+
+// getHomeSubDir resolves res to the default path subPath under $HOME.
+func (d *linuxDirs) getHomeSubDir(res Resolution, subPath string) (Resolution, error) {
+	home, err := d.resolve(Home)
+	if err != nil {
+		return res, err
+	}
+	res.derive(SourceDefault, "$HOME/"+subPath, d.join(home.Path, subPath), home)
+	return res, nil
+}
+
+// getDerivedDir resolves kind as the directory of kind base, optionally
+// followed by subPath.
+func (d *linuxDirs) getDerivedDir(kind, base Kind, subPath ...string) (Resolution, error) {
+	res := Resolution{Kind: kind}
+	baseRes, err := d.resolve(base)
+	if err != nil {
+		return res, err
+	}
+	detail := path.Join(append([]string{base.String()}, subPath...)...)
+	res.derive(SourceDerived, detail, d.join(append([]string{baseRes.Path}, subPath...)...), baseRes)
+	return res, nil
+}
+
+// getUserDir checks the XDG environment variable, then the user-dirs.dirs
+// file maintained by xdg-user-dirs, and finally falls back to a default path.
+func (d *linuxDirs) getUserDir(kind Kind, envVar, defaultSubPath string) (Resolution, error) {
+	res := Resolution{Kind: kind}
+	if d.lookupEnvDir(&res, envVar) {
+		return res, nil
+	}
+	home, err := d.resolve(Home)
+	if err != nil {
+		return res, err
+	}
+	if d.lookupUserDirsFile(&res, envVar, home.Path) {
+		return res, nil
+	}
+	return d.getHomeSubDir(res, defaultSubPath)
+}
+
+// lookupUserDirsFile reads $XDG_CONFIG_HOME/user-dirs.dirs and, if it has an
+// entry for envVar, records it in res. A missing or unreadable file is not
+// an error; the caller simply falls back to its default. Layouts from
+// NewDirsFor do not read the file, as it would be the host's.
+func (d *linuxDirs) lookupUserDirsFile(res *Resolution, envVar, home string) bool {
+	if d.pure {
+		return false
+	}
+	configDir, err := d.resolve(Config)
+	if err != nil {
+		return false
+	}
+	file := d.join(configDir.Path, userDirsFile)
+	f, err := os.Open(file)
+	if err != nil {
+		res.skip(SourceFile, file, "", "cannot be read")
+		return false
+	}
+	defer f.Close()
+	entries, err := parseUserDirs(f, home)
+	if err != nil {
+		res.skip(SourceFile, file, "", "cannot be parsed: "+err.Error())
+		return false
+	}
+	entry, ok := entries[envVar]
+	if !ok || entry.path == "" {
+		res.skip(SourceFile, file, "", "no "+envVar+" entry")
+		return false
+	}
+	res.use(SourceFile, fmt.Sprintf("%s:%d", file, entry.line), entry.path)
+	return true
+}
+
+func (d *linuxDirs) systemConfigDirs() ([]string, error) {
+	return d.getSearchDirs("XDG_CONFIG_DIRS", []string{"/etc/xdg"}), nil
+}
+
+func (d *linuxDirs) systemDataDirs() ([]string, error) {
+	return d.getSearchDirs("XDG_DATA_DIRS", []string{"/usr/local/share", "/usr/share"}), nil
+}
+
+// getSearchDirs splits a colon-separated XDG search path. Per the spec, an
+// unset or empty variable means the defaults, and relative entries are
+// ignored. If no usable entry remains the defaults are returned as well.
+func (d *linuxDirs) getSearchDirs(envVar string, defaults []string) []string {
+	var dirs []string
+	for _, dir := range strings.Split(d.getenv(envVar), ":") {
+		if dir != "" && d.isAbs(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		return defaults
+	}
+	return dirs
+}
+
+func (d *linuxDirs) platform() Platform {
+	return Linux
+}
+
+func (d *linuxDirs) projectPath(qualifier, organization, application string) string {
+	return xdgProjectPath(qualifier, organization, application)
+}
+
+func (d *linuxDirs) join(elem ...string) string {
+	if d.host != nil {
+		return d.host.join(elem...)
+	}
+	return path.Join(elem...)
+}
+
+// isAbs reports whether dir is an absolute path in the syntax of the
+// platform.
+func (d *linuxDirs) isAbs(dir string) bool {
+	if d.host != nil && d.host.platform() == Windows {
+		return windowsIsAbs(dir)
+	}
+	return path.IsAbs(dir)
+}
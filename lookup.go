@@ -0,0 +1,98 @@
+package dirs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ConfigPaths returns the configuration search path in order of decreasing
+// precedence: the user's ConfigDir followed by SystemConfigDirs.
+func ConfigPaths(d Dirs) ([]string, error) {
+	return searchPaths(d.ConfigDir, d.SystemConfigDirs)
+}
+
+// DataPaths returns the data search path in order of decreasing precedence:
+// the user's DataDir followed by SystemDataDirs.
+func DataPaths(d Dirs) ([]string, error) {
+	return searchPaths(d.DataDir, d.SystemDataDirs)
+}
+
+// FindConfigFile returns the first existing path for name, which is relative
+// to the configuration directories (e.g. "myapp/config.toml"). If no
+// candidate exists the error satisfies errors.Is(err, fs.ErrNotExist).
+func FindConfigFile(d Dirs, name string) (string, error) {
+	return findFirst(ConfigPaths, d, name)
+}
+
+// FindConfigFiles returns every existing path for name across the
+// configuration directories, in order of decreasing precedence.
+func FindConfigFiles(d Dirs, name string) ([]string, error) {
+	return findAll(ConfigPaths, d, name)
+}
+
+// FindDataFile returns the first existing path for name, which is relative
+// to the data directories. If no candidate exists the error satisfies
+// errors.Is(err, fs.ErrNotExist).
+func FindDataFile(d Dirs, name string) (string, error) {
+	return findFirst(DataPaths, d, name)
+}
+
+// FindDataFiles returns every existing path for name across the data
+// directories, in order of decreasing precedence.
+func FindDataFiles(d Dirs, name string) ([]string, error) {
+	return findAll(DataPaths, d, name)
+}
+
+// searchPaths combines a user directory with the system search path,
+// skipping empty and duplicate entries.
+func searchPaths(user func() (string, error), system func() ([]string, error)) ([]string, error) {
+	userDir, err := user()
+	if err != nil {
+		return nil, err
+	}
+	systemDirs, err := system()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, dir := range append([]string{userDir}, systemDirs...) {
+		if dir == "" || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		paths = append(paths, dir)
+	}
+	return paths, nil
+}
+
+func findFirst(search func(Dirs) ([]string, error), d Dirs, name string) (string, error) {
+	dirs, err := search(d)
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", &fs.PathError{Op: "find", Path: name, Err: fs.ErrNotExist}
+}
+
+func findAll(search func(Dirs) ([]string, error), d Dirs, name string) ([]string, error) {
+	dirs, err := search(d)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,258 @@
+package dirs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MigrationBreadcrumb is the name of the file Migrate leaves in the legacy
+// directory once everything has been moved. It lists the new locations for
+// users who look in the old place, and marks the migration as done.
+const MigrationBreadcrumb = "MIGRATED.txt"
+
+// Migration describes how to move an application's files from a legacy
+// location, typically a dotfile directory such as ~/.mytool, to the
+// directories of a Locator.
+type Migration struct {
+	// Legacy is the directory to migrate from.
+	Legacy string
+	// Mapping maps paths relative to Legacy, such as "config.toml" or
+	// "plugins", to the kind of directory they belong in. Each entry keeps
+	// its relative path below the new directory, so with a ProjectDirs
+	// "config.toml" moves to ~/.config/mytool/config.toml on Linux.
+	Mapping map[string]Kind
+	// DryRun makes Migrate report what it would do without changing
+	// anything.
+	DryRun bool
+}
+
+// MigrationStep reports what happened to one entry of a Migration.Mapping.
+type MigrationStep struct {
+	Kind Kind
+	From string
+	To   string
+	// Skipped explains why the entry was left alone, e.g. because To
+	// already exists. It is empty if the entry was moved, or in a dry run
+	// would be moved.
+	Skipped string
+	// Copied reports that From was on a different filesystem than To, so
+	// it was copied and then removed instead of renamed.
+	Copied bool
+}
+
+func (s MigrationStep) String() string {
+	switch {
+	case s.Skipped != "":
+		return fmt.Sprintf("skip %s: %s", s.From, s.Skipped)
+	case s.Copied:
+		return fmt.Sprintf("copy %s -> %s", s.From, s.To)
+	}
+	return fmt.Sprintf("move %s -> %s", s.From, s.To)
+}
+
+// Migrate moves the legacy entries described by m into the directories of l,
+// creating them with EnsureDir as needed. Entries are processed in lexical
+// order of their legacy paths. An entry that does not exist is ignored, and
+// one whose destination already exists is skipped rather than overwritten.
+//
+// Each entry is renamed, which is atomic. If the destination is on another
+// filesystem, the entry is instead copied into a temporary name beside the
+// destination, renamed into place and only then removed from the legacy
+// directory, so an interruption never leaves a partial copy at the new path.
+//
+// Once every entry has been handled, Migrate writes MigrationBreadcrumb into
+// the legacy directory. If the legacy directory does not exist or already
+// contains the breadcrumb, there is nothing to do and Migrate returns no
+// steps. On error, the steps completed so far are returned and Migrate can
+// be run again to finish the job.
+func Migrate(l Locator, m Migration) ([]MigrationStep, error) {
+	if fi, err := os.Stat(m.Legacy); err != nil || !fi.IsDir() {
+		if err == nil || errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("dirs: migrate: %w", err)
+	}
+	if _, err := os.Lstat(filepath.Join(m.Legacy, MigrationBreadcrumb)); err == nil {
+		return nil, nil
+	}
+
+	subPaths := make([]string, 0, len(m.Mapping))
+	for sub := range m.Mapping {
+		if !filepath.IsLocal(sub) {
+			return nil, fmt.Errorf("dirs: migrate: %q is not a path within %s", sub, m.Legacy)
+		}
+		subPaths = append(subPaths, sub)
+	}
+	sort.Strings(subPaths)
+
+	var steps []MigrationStep
+	for _, sub := range subPaths {
+		step, err := migrateEntry(l, m, sub)
+		if err != nil {
+			return steps, fmt.Errorf("dirs: migrate %s: %w", step.From, err)
+		}
+		if step.From != "" {
+			steps = append(steps, step)
+		}
+	}
+	if m.DryRun || len(steps) == 0 {
+		return steps, nil
+	}
+	if err := writeBreadcrumb(m.Legacy, steps); err != nil {
+		return steps, fmt.Errorf("dirs: migrate: %w", err)
+	}
+	return steps, nil
+}
+
+// migrateEntry migrates the legacy entry sub. A zero step with a nil error
+// means the entry does not exist.
+func migrateEntry(l Locator, m Migration, sub string) (MigrationStep, error) {
+	kind := m.Mapping[sub]
+	step := MigrationStep{Kind: kind, From: filepath.Join(m.Legacy, sub)}
+	if _, err := os.Lstat(step.From); errors.Is(err, fs.ErrNotExist) {
+		return MigrationStep{}, nil
+	} else if err != nil {
+		return step, err
+	}
+
+	dir, err := l.Dir(kind)
+	if err == nil && !m.DryRun {
+		dir, err = ensureDir(l, kind)
+	}
+	if err != nil {
+		return step, err
+	}
+	if dir == "" {
+		step.Skipped = kind.String() + " directory not available"
+		return step, nil
+	}
+	step.To = filepath.Join(dir, sub)
+	if _, err := os.Lstat(step.To); err == nil {
+		step.Skipped = "destination already exists"
+		return step, nil
+	}
+	if m.DryRun {
+		return step, nil
+	}
+
+	owner := ownerOf(l)
+	mode := dirMode(kind)
+	if _, ok := l.(*SystemDirs); ok {
+		mode = systemDirMode
+	}
+	if err := mkdirAllOwned(filepath.Dir(step.To), mode, owner); err != nil {
+		return step, err
+	}
+	err = os.Rename(step.From, step.To)
+	if err == nil || !crossDevice(err) {
+		return step, err
+	}
+	step.Copied = true
+	return step, moveAcrossDevices(step.From, step.To, owner)
+}
+
+// moveAcrossDevices moves from to to on another filesystem. The copy is
+// made under a temporary name in the destination directory and renamed into
+// place, which is atomic there, before from is removed. The copy is owned
+// by owner, if it is not nil.
+func moveAcrossDevices(from, to string, owner *passwdEntry) error {
+	tmp, err := os.MkdirTemp(filepath.Dir(to), "."+filepath.Base(to)+".migrating-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	staged := filepath.Join(tmp, filepath.Base(to))
+	if err := copyTree(from, staged, owner); err != nil {
+		return err
+	}
+	if err := os.Rename(staged, to); err != nil {
+		return err
+	}
+	return os.RemoveAll(from)
+}
+
+// copyTree copies the file, directory or symlink from to to, preserving
+// permission bits, and chowns the copy to owner, if it is not nil.
+func copyTree(from, to string, owner *passwdEntry) error {
+	fi, err := os.Lstat(from)
+	if err != nil {
+		return err
+	}
+	if err := copyEntry(from, to, fi, owner); err != nil {
+		return err
+	}
+	if owner != nil {
+		return os.Lchown(to, owner.uid, owner.gid)
+	}
+	return nil
+}
+
+// copyEntry copies the entry from, described by fi, to to.
+func copyEntry(from, to string, fi fs.FileInfo, owner *passwdEntry) error {
+	switch {
+	case fi.Mode()&fs.ModeSymlink != 0:
+		target, err := os.Readlink(from)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, to)
+	case fi.IsDir():
+		if err := os.Mkdir(to, fi.Mode().Perm()); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(from)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := copyTree(filepath.Join(from, e.Name()), filepath.Join(to, e.Name()), owner); err != nil {
+				return err
+			}
+		}
+		return nil
+	case fi.Mode().IsRegular():
+		return copyFile(from, to, fi.Mode().Perm())
+	}
+	return fmt.Errorf("%s: cannot copy %s", from, fi.Mode().Type())
+}
+
+func copyFile(from, to string, perm fs.FileMode) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// writeBreadcrumb records in legacy where its contents went.
+func writeBreadcrumb(legacy string, steps []MigrationStep) error {
+	var b strings.Builder
+	b.WriteString("The files in this directory have moved:\n\n")
+	for _, s := range steps {
+		if s.Skipped == "" {
+			fmt.Fprintf(&b, "%s -> %s\n", s.From, s.To)
+		} else {
+			fmt.Fprintf(&b, "%s was left here: %s\n", s.From, s.Skipped)
+		}
+	}
+	return os.WriteFile(filepath.Join(legacy, MigrationBreadcrumb), []byte(b.String()), 0o644)
+}
@@ -0,0 +1,157 @@
+package dirs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	root := t.TempDir()
+	legacy := filepath.Join(root, ".mytool")
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(legacy, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("config.toml", "a = 1")
+	write("plugins/one.so", "plugin")
+	write("history", "old")
+
+	paths := map[Kind]string{
+		Config: filepath.Join(root, "config"),
+		Data:   filepath.Join(root, "data"),
+		State:  filepath.Join(root, "state"),
+	}
+	l := locatorFunc(func(kind Kind) (string, error) { return paths[kind], nil })
+	if err := os.MkdirAll(paths[State], 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(paths[State], "history"), []byte("new"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := Migration{
+		Legacy: legacy,
+		Mapping: map[string]Kind{
+			"config.toml": Config,
+			"plugins":     Data,
+			"history":     State,
+			"missing":     Cache,
+		},
+		DryRun: true,
+	}
+
+	t.Run("DryRun", func(t *testing.T) {
+		steps, err := Migrate(l, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(steps) != 3 {
+			t.Fatalf("Expected 3 steps, got %v", steps)
+		}
+		if _, err := os.Stat(paths[Config]); !os.IsNotExist(err) {
+			t.Errorf("Expected a dry run to create nothing, got %v", err)
+		}
+	})
+
+	m.DryRun = false
+	steps, err := Migrate(l, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, s := range steps {
+		got = append(got, s.String())
+	}
+	expected := []string{
+		"move " + filepath.Join(legacy, "config.toml") + " -> " + filepath.Join(paths[Config], "config.toml"),
+		"skip " + filepath.Join(legacy, "history") + ": destination already exists",
+		"move " + filepath.Join(legacy, "plugins") + " -> " + filepath.Join(paths[Data], "plugins"),
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected steps:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+
+	if b, err := os.ReadFile(filepath.Join(paths[Data], "plugins", "one.so")); err != nil || string(b) != "plugin" {
+		t.Errorf("Expected the plugins directory to be moved, got (%q, %v)", b, err)
+	}
+	if b, err := os.ReadFile(filepath.Join(paths[State], "history")); err != nil || string(b) != "new" {
+		t.Errorf("Expected the existing history to be kept, got (%q, %v)", b, err)
+	}
+	breadcrumb, err := os.ReadFile(filepath.Join(legacy, MigrationBreadcrumb))
+	if err != nil || !strings.Contains(string(breadcrumb), filepath.Join(paths[Config], "config.toml")) {
+		t.Errorf("Expected a breadcrumb naming the new config path, got (%q, %v)", breadcrumb, err)
+	}
+
+	t.Run("AlreadyMigrated", func(t *testing.T) {
+		steps, err := Migrate(l, m)
+		if err != nil || len(steps) != 0 {
+			t.Errorf("Expected nothing to do, got (%v, %v)", steps, err)
+		}
+	})
+
+	t.Run("NoLegacy", func(t *testing.T) {
+		steps, err := Migrate(l, Migration{Legacy: filepath.Join(root, ".absent"), Mapping: m.Mapping})
+		if err != nil || len(steps) != 0 {
+			t.Errorf("Expected nothing to do, got (%v, %v)", steps, err)
+		}
+	})
+
+	t.Run("EscapingPath", func(t *testing.T) {
+		other := filepath.Join(root, ".other")
+		if err := os.Mkdir(other, 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Migrate(l, Migration{Legacy: other, Mapping: map[string]Kind{"../config": Config}}); err == nil {
+			t.Error("Expected an error for a path outside the legacy directory")
+		}
+	})
+}
+
+func TestMoveAcrossDevices(t *testing.T) {
+	root := t.TempDir()
+	from := filepath.Join(root, "from")
+	if err := os.MkdirAll(filepath.Join(from, "sub"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(from, "sub", "secret"), []byte("s"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink("sub/secret", filepath.Join(from, "link")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	to := filepath.Join(root, "to")
+	if err := moveAcrossDevices(from, to, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(from); !os.IsNotExist(err) {
+		t.Errorf("Expected the source to be removed, got %v", err)
+	}
+	fi, err := os.Stat(filepath.Join(to, "sub", "secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" {
+		if fi.Mode().Perm() != 0o600 {
+			t.Errorf("Expected mode 0600 to be preserved, got %04o", fi.Mode().Perm())
+		}
+		if target, err := os.Readlink(filepath.Join(to, "link")); err != nil || target != "sub/secret" {
+			t.Errorf("Expected the symlink to be preserved, got (%s, %v)", target, err)
+		}
+	}
+	entries, _ := os.ReadDir(root)
+	if len(entries) != 1 {
+		t.Errorf("Expected no staging directory to be left behind, got %v", entries)
+	}
+}
@@ -0,0 +1,153 @@
+//go:build linux
+
+package dirs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// maxSymlinks bounds the symlinks mkdirAllAs follows, as the kernel
+	// does.
+	maxSymlinks = 40
+	// oPath is O_PATH, which package syscall lacks. Its value is the same
+	// on all the architectures Go supports.
+	oPath = 0x200000
+)
+
+// mkdirAllAs is os.MkdirAll for the directories of user u, run by root, with
+// the directories it creates owned by u. It walks dir one component at a
+// time from file descriptors rather than paths, and never follows a symlink
+// that root does not own, so that u cannot redirect the directories it
+// creates into a tree u does not control. The target of a symlink owned by
+// root is walked the same way.
+func mkdirAllAs(dir string, mode os.FileMode, u *passwdEntry) error {
+	if !path.IsAbs(dir) {
+		return &os.PathError{Op: "mkdir", Path: dir, Err: errors.New("not an absolute path")}
+	}
+	fd, err := syscall.Open("/", syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return &os.PathError{Op: "open", Path: "/", Err: err}
+	}
+	defer func() { syscall.Close(fd) }()
+
+	cur := "/"
+	names := strings.Split(dir, "/")
+	for links := 0; len(names) > 0; {
+		name := names[0]
+		names = names[1:]
+		if name == "" || name == "." {
+			continue
+		}
+		p := path.Join(cur, name)
+		next, err := openDirAt(fd, name)
+		if errors.Is(err, syscall.ENOENT) {
+			next, err = mkdirAt(fd, name, mode, u)
+		}
+		if errors.Is(err, syscall.ELOOP) || errors.Is(err, syscall.ENOTDIR) {
+			var target string
+			if target, err = readTrustedLink(fd, name, p); err != nil {
+				return err
+			}
+			if links++; links > maxSymlinks {
+				return &os.PathError{Op: "mkdir", Path: dir, Err: syscall.ELOOP}
+			}
+			if path.IsAbs(target) {
+				root, err := syscall.Open("/", syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+				if err != nil {
+					return &os.PathError{Op: "open", Path: "/", Err: err}
+				}
+				syscall.Close(fd)
+				fd, cur = root, "/"
+			}
+			names = append(strings.Split(target, "/"), names...)
+			continue
+		}
+		if err != nil {
+			return &os.PathError{Op: "mkdir", Path: p, Err: err}
+		}
+		syscall.Close(fd)
+		fd, cur = next, p
+	}
+	return nil
+}
+
+// openDirAt opens the directory name in the directory dirfd without
+// following a symlink.
+func openDirAt(dirfd int, name string) (int, error) {
+	return syscall.Openat(dirfd, name, syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_NOFOLLOW|syscall.O_CLOEXEC, 0)
+}
+
+// mkdirAt creates the directory name in the directory dirfd, owned by u,
+// and opens it.
+func mkdirAt(dirfd int, name string, mode os.FileMode, u *passwdEntry) (int, error) {
+	err := syscall.Mkdirat(dirfd, name, uint32(mode.Perm()))
+	if errors.Is(err, syscall.EEXIST) {
+		// Created concurrently, so it is not ours to chown.
+		return openDirAt(dirfd, name)
+	}
+	if err != nil {
+		return -1, err
+	}
+	fd, err := openDirAt(dirfd, name)
+	if err != nil {
+		return -1, err
+	}
+	if err := syscall.Fchown(fd, u.uid, u.gid); err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+	return fd, nil
+}
+
+// readTrustedLink returns the target of the symlink name in the directory
+// dirfd, at path p, if it is owned by root. Other symlinks, which the user
+// could have planted, report ErrInsecure.
+func readTrustedLink(dirfd int, name, p string) (string, error) {
+	fd, err := syscall.Openat(dirfd, name, oPath|syscall.O_NOFOLLOW|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return "", &os.PathError{Op: "open", Path: p, Err: err}
+	}
+	defer syscall.Close(fd)
+	var st syscall.Stat_t
+	if err := syscall.Fstat(fd, &st); err != nil {
+		return "", &os.PathError{Op: "stat", Path: p, Err: err}
+	}
+	switch {
+	case st.Mode&syscall.S_IFMT != syscall.S_IFLNK:
+		return "", &os.PathError{Op: "mkdir", Path: p, Err: syscall.ENOTDIR}
+	case st.Uid != 0:
+		return "", fmt.Errorf("%w: %s is a symlink owned by uid %d", ErrInsecure, p, st.Uid)
+	}
+	target, err := readlinkFd(fd)
+	if err != nil {
+		return "", &os.PathError{Op: "readlink", Path: p, Err: err}
+	}
+	return target, nil
+}
+
+// readlinkFd reads the target of the symlink opened as fd with O_PATH, which
+// unlike a lookup by name cannot be swapped for another link in between.
+func readlinkFd(fd int) (string, error) {
+	empty, err := syscall.BytePtrFromString("")
+	if err != nil {
+		return "", err
+	}
+	for size := 256; ; size *= 2 {
+		buf := make([]byte, size)
+		n, _, errno := syscall.Syscall6(syscall.SYS_READLINKAT, uintptr(fd), uintptr(unsafe.Pointer(empty)),
+			uintptr(unsafe.Pointer(&buf[0])), uintptr(size), 0, 0)
+		if errno != 0 {
+			return "", errno
+		}
+		if int(n) < size {
+			return string(buf[:n]), nil
+		}
+	}
+}
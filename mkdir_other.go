@@ -0,0 +1,14 @@
+//go:build !linux
+
+package dirs
+
+import (
+	"fmt"
+	"os"
+)
+
+// mkdirAllAs is only needed for the directories of ForUser, which is
+// supported on Linux only.
+func mkdirAllAs(dir string, mode os.FileMode, u *passwdEntry) error {
+	return fmt.Errorf("dirs: %s: %w", dir, ErrNotSupported)
+}
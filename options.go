@@ -0,0 +1,126 @@
+package dirs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Env looks up the value of an environment variable, reporting whether it is
+// set. It has the same semantics as os.LookupEnv, which is used by default.
+type Env func(key string) (string, bool)
+
+// MapEnv returns an Env that looks up variables in m.
+func MapEnv(m map[string]string) Env {
+	return func(key string) (string, bool) {
+		value, ok := m[key]
+		return value, ok
+	}
+}
+
+// HomeFunc resolves the current user's home directory.
+type HomeFunc func() (string, error)
+
+// Option configures the Dirs returned by New.
+type Option func(*options)
+
+// WithEnv makes Dirs read environment variables from env instead of the
+// process environment. Unless WithHomeDir is also given, the home directory
+// is then taken from env as well, without the passwd fallback used on Linux
+// when $HOME is unset.
+func WithEnv(env Env) Option {
+	return func(o *options) {
+		o.env = env
+	}
+}
+
+// WithHomeDir makes Dirs resolve the home directory with home instead of
+// os.UserHomeDir.
+func WithHomeDir(home HomeFunc) Option {
+	return func(o *options) {
+		o.home = home
+	}
+}
+
+// options holds the configuration shared by all platform implementations.
+type options struct {
+	env              Env
+	home             HomeFunc
+	emptyUnsupported bool
+	runtimeFallback  bool
+	portableMarker   string
+	preset           Preset
+
+	// passwdPath is the passwd database for the Linux home directory
+	// fallback; empty means /etc/passwd.
+	passwdPath string
+	// secure ignores the environment, as described for WithSecureMode.
+	secure bool
+
+	// user is the passwd entry of the user selected by ForUser, or nil for
+	// the current user.
+	user *passwdEntry
+
+	// executable locates the running program for portable mode; nil means
+	// os.Executable.
+	executable func() (string, error)
+
+	// pure disables checks against the local filesystem, for layouts that
+	// are computed for another system by NewDirsFor.
+	pure bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *options) isPure() bool {
+	return o.pure
+}
+
+// getenv returns the value of key, or an empty string if it is not set.
+func (o *options) getenv(key string) string {
+	if o.env == nil {
+		return os.Getenv(key)
+	}
+	value, _ := o.env(key)
+	return value
+}
+
+// resolveHome resolves the home directory. With a custom environment and
+// no custom resolver, it is read from envVar, mirroring os.UserHomeDir.
+// Errors wrap ErrHomeNotFound.
+func (o *options) resolveHome(envVar string) (Resolution, error) {
+	res := Resolution{Kind: Home}
+	var (
+		dir string
+		err error
+	)
+	switch {
+	case o.home != nil:
+		dir, err = o.home()
+		res.use(SourceOverride, "WithHomeDir", dir)
+	case o.env == nil:
+		// os.UserHomeDir reads the same variable on the supported platforms.
+		dir, err = os.UserHomeDir()
+		res.use(SourceEnv, envVar, dir)
+	default:
+		if dir = o.getenv(envVar); dir == "" {
+			err = fmt.Errorf("$%s is not defined", envVar)
+		}
+		res.use(SourceEnv, envVar, dir)
+	}
+	if err == nil && dir == "" {
+		err = errors.New("resolver returned an empty path")
+	}
+	if err != nil {
+		res.skip(res.Source, res.Detail, "", err.Error())
+		res.use(SourceNone, "", "")
+		return res, &DirError{Kind: Home, Err: fmt.Errorf("%w: %w", ErrHomeNotFound, err)}
+	}
+	return res, nil
+}
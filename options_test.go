@@ -0,0 +1,50 @@
+package dirs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMapEnv(t *testing.T) {
+	env := MapEnv(map[string]string{"SET": "value", "EMPTY": ""})
+
+	if value, ok := env("SET"); !ok || value != "value" {
+		t.Errorf("Expected (value, true), got (%s, %v)", value, ok)
+	}
+	if value, ok := env("EMPTY"); !ok || value != "" {
+		t.Errorf("Expected (\"\", true), got (%s, %v)", value, ok)
+	}
+	if value, ok := env("UNSET"); ok || value != "" {
+		t.Errorf("Expected (\"\", false), got (%s, %v)", value, ok)
+	}
+}
+
+func TestOptionsHomeDir(t *testing.T) {
+	t.Parallel()
+
+	t.Run("FromEnv", func(t *testing.T) {
+		o := newOptions([]Option{WithEnv(MapEnv(map[string]string{"HOME": "/home/env"}))})
+		home, err := o.resolveHome("HOME")
+		if err != nil || home.Path != "/home/env" {
+			t.Errorf("Expected (/home/env, nil), got (%s, %v)", home.Path, err)
+		}
+	})
+
+	t.Run("MissingFromEnv", func(t *testing.T) {
+		o := newOptions([]Option{WithEnv(MapEnv(nil))})
+		if _, err := o.resolveHome("HOME"); err == nil {
+			t.Error("Expected an error when HOME is not in the environment")
+		}
+	})
+
+	t.Run("Resolver", func(t *testing.T) {
+		errHome := errors.New("no home")
+		o := newOptions([]Option{
+			WithEnv(MapEnv(map[string]string{"HOME": "/home/env"})),
+			WithHomeDir(func() (string, error) { return "", errHome }),
+		})
+		if _, err := o.resolveHome("HOME"); !errors.Is(err, errHome) {
+			t.Errorf("Expected resolver error, got %v", err)
+		}
+	})
+}
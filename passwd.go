@@ -0,0 +1,96 @@
+package dirs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultPasswdFile is the local passwd database.
+const defaultPasswdFile = "/etc/passwd"
+
+// passwdEntry is a user read from a passwd file.
+type passwdEntry struct {
+	name string
+	uid  int
+	gid  int
+	home string
+	line int // 1-based line number, for provenance
+}
+
+// errNoPasswdEntry is returned by lookupPasswd when no entry matches.
+var errNoPasswdEntry = errors.New("no matching entry")
+
+// parsePasswd returns the first entry of a passwd file in r for which match
+// returns true. Lines are of the form name:password:uid:gid:gecos:home:shell;
+// comments, NIS compat entries starting with + or -, and malformed lines are
+// ignored.
+func parsePasswd(r io.Reader, match func(passwdEntry) bool) (passwdEntry, error) {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "+") || strings.HasPrefix(text, "-") {
+			continue
+		}
+		fields := strings.Split(text, ":")
+		if len(fields) != 7 {
+			continue
+		}
+		uid, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		gid, err := strconv.Atoi(fields[3])
+		if err != nil {
+			continue
+		}
+		entry := passwdEntry{name: fields[0], uid: uid, gid: gid, home: fields[5], line: line}
+		if match(entry) {
+			return entry, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return passwdEntry{}, err
+	}
+	return passwdEntry{}, errNoPasswdEntry
+}
+
+// lookupPasswd is parsePasswd on the file at path.
+func lookupPasswd(path string, match func(passwdEntry) bool) (passwdEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return passwdEntry{}, err
+	}
+	defer f.Close()
+	return parsePasswd(f, match)
+}
+
+// passwdFile returns the passwd database to consult.
+func (o *options) passwdFile() string {
+	if o.passwdPath != "" {
+		return o.passwdPath
+	}
+	return defaultPasswdFile
+}
+
+// lookupPasswdHome records in res the home directory of uid according to
+// the passwd database. It is the last resort for the home directory on
+// Linux, where HOME is often unset in containers and system services.
+func (o *options) lookupPasswdHome(res *Resolution, uid int) bool {
+	file := o.passwdFile()
+	entry, err := lookupPasswd(file, func(e passwdEntry) bool { return e.uid == uid })
+	switch {
+	case err != nil:
+		res.skip(SourceFile, file, "", fmt.Sprintf("uid %d: %v", uid, err))
+		return false
+	case !strings.HasPrefix(entry.home, "/"):
+		res.skip(SourceFile, fmt.Sprintf("%s:%d", file, entry.line), entry.home, "home directory is not an absolute path")
+		return false
+	}
+	res.use(SourceFile, fmt.Sprintf("%s:%d", file, entry.line), entry.home)
+	return true
+}
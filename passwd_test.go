@@ -0,0 +1,29 @@
+package dirs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParsePasswd(t *testing.T) {
+	const passwd = `# comment
+root:x:0:0:root:/root:/bin/bash
++@netgroup::::::
+broken:x:abc:0::/nowhere:/bin/false
+short:x:5:5
+me:x:1000:1000:Me,,,:/home/me:/bin/zsh
+`
+	entry, err := parsePasswd(strings.NewReader(passwd), func(e passwdEntry) bool { return e.uid == 1000 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := passwdEntry{name: "me", uid: 1000, gid: 1000, home: "/home/me", line: 6}
+	if entry != expected {
+		t.Errorf("Expected %+v, got %+v", expected, entry)
+	}
+
+	if _, err := parsePasswd(strings.NewReader(passwd), func(e passwdEntry) bool { return e.name == "broken" }); !errors.Is(err, errNoPasswdEntry) {
+		t.Errorf("Expected malformed entries to be ignored, got %v", err)
+	}
+}
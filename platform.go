@@ -0,0 +1,83 @@
+package dirs
+
+import "fmt"
+
+// Platform identifies an operating system whose directory layout this
+// package knows how to compute.
+type Platform string
+
+const (
+	Linux   Platform = "linux"
+	Darwin  Platform = "darwin"
+	Windows Platform = "windows"
+)
+
+// CurrentPlatform returns the platform the program was built for.
+func CurrentPlatform() Platform {
+	return currentPlatform
+}
+
+// layout is implemented by the directory layout of every supported
+// platform. layoutDirs turns a layout into a Dirs.
+type layout interface {
+	// resolve resolves a directory of a valid kind.
+	resolve(kind Kind) (Resolution, error)
+	systemConfigDirs() ([]string, error)
+	systemDataDirs() ([]string, error)
+	// platform returns the platform whose conventions the layout follows.
+	platform() Platform
+	// projectPath returns the platform's name for an application directory.
+	projectPath(qualifier, organization, application string) string
+	// join joins path elements using the platform's separator.
+	join(elem ...string) string
+	// isAbs reports whether dir is an absolute path in the platform's
+	// syntax.
+	isAbs(dir string) bool
+	// getenv returns the value of an environment variable, as configured
+	// by WithEnv.
+	getenv(key string) string
+	// owner returns the user selected by ForUser, or nil for the current
+	// user.
+	owner() *passwdEntry
+	// isPure reports whether the layout was computed for another system
+	// by NewDirsFor, so that its paths must not be used locally.
+	isPure() bool
+}
+
+// NewDirsFor returns the Dirs of platform p, computed purely from env and
+// opts rather than from the running system. This allows, for example,
+// computing the paths an application will use on Windows from a Linux host.
+// The home directory is taken from env ($HOME, or %USERPROFILE% on Windows)
+// unless WithHomeDir is given. A nil env is treated as an empty environment.
+// Checks that need the local filesystem, such as validating XDG_RUNTIME_DIR,
+// are skipped, and EnsureDir reports ErrNotSupported.
+func NewDirsFor(p Platform, env Env, opts ...Option) (Dirs, error) {
+	if env == nil {
+		env = MapEnv(nil)
+	}
+	o := newOptions(append([]Option{WithEnv(env)}, opts...))
+	o.pure = true
+	return newLayoutDirs(p, o)
+}
+
+func newLayoutDirs(p Platform, o *options) (*layoutDirs, error) {
+	applySecureMode(p, o)
+	var l layout
+	switch p {
+	case Linux:
+		l = &linuxDirs{options: o}
+	case Darwin:
+		l = &darwinDirs{o}
+	case Windows:
+		l = &windowsDirs{o}
+	default:
+		return nil, fmt.Errorf("dirs: unsupported platform %q", string(p))
+	}
+	l = applyPreset(l, o)
+	if o.portableMarker != "" && !o.pure {
+		if root, marker, ok := o.findPortableRoot(); ok {
+			l = &portableLayout{layout: l, root: root, marker: marker}
+		}
+	}
+	return &layoutDirs{l}, nil
+}
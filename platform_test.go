@@ -0,0 +1,146 @@
+package dirs
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewDirsFor(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewDirsFor(Platform("plan9"), nil); err == nil {
+		t.Error("Expected an error for an unsupported platform")
+	}
+
+	tests := []struct {
+		platform Platform
+		env      map[string]string
+		expected map[string]string
+	}{
+		{
+			platform: Linux,
+			env:      map[string]string{"HOME": "/home/me", "XDG_CACHE_HOME": "/tmp/cache"},
+			expected: map[string]string{
+				"HomeDir":   "/home/me",
+				"CacheDir":  "/tmp/cache",
+				"ConfigDir": "/home/me/.config",
+				"FontDir":   "/home/me/.local/share/fonts",
+				"VideoDir":  "/home/me/Videos",
+			},
+		},
+		{
+			platform: Darwin,
+			env:      map[string]string{"HOME": "/Users/me", "XDG_CACHE_HOME": "/tmp/cache"},
+			expected: map[string]string{
+				"HomeDir":       "/Users/me",
+				"CacheDir":      "/Users/me/Library/Caches",
+				"ConfigDir":     "/Users/me/Library/Application Support",
+				"PreferenceDir": "/Users/me/Library/Preferences",
+				"VideoDir":      "/Users/me/Movies",
+			},
+		},
+		{
+			platform: Windows,
+			env:      map[string]string{"USERPROFILE": `C:\Users\me`, "APPDATA": `D:\Roaming`, "PUBLIC": `C:\Users\Public`},
+			expected: map[string]string{
+				"HomeDir":     `C:\Users\me`,
+				"CacheDir":    `C:\Users\me\AppData\Local`,
+				"ConfigDir":   `D:\Roaming`,
+				"PublicDir":   `C:\Users\Public`,
+				"TemplateDir": `D:\Roaming\Microsoft\Windows\Templates`,
+				"VideoDir":    `C:\Users\me\Videos`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.platform), func(t *testing.T) {
+			d, err := NewDirsFor(tt.platform, MapEnv(tt.env))
+			if err != nil {
+				t.Fatalf("NewDirsFor returned error: %v", err)
+			}
+			getters := map[string]func() (string, error){
+				"HomeDir":       d.HomeDir,
+				"CacheDir":      d.CacheDir,
+				"ConfigDir":     d.ConfigDir,
+				"FontDir":       d.FontDir,
+				"PreferenceDir": d.PreferenceDir,
+				"PublicDir":     d.PublicDir,
+				"TemplateDir":   d.TemplateDir,
+				"VideoDir":      d.VideoDir,
+			}
+			for name, expected := range tt.expected {
+				path, err := getters[name]()
+				if err != nil {
+					t.Errorf("%s returned error: %v", name, err)
+				}
+				if path != expected {
+					t.Errorf("%s expected '%s', got '%s'", name, expected, path)
+				}
+			}
+		})
+	}
+}
+
+func TestNewDirsForIgnoresHostFiles(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(home, ".config"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	userDirs := []byte("XDG_MUSIC_DIR=\"$HOME/Tunes\"\n")
+	if err := os.WriteFile(filepath.Join(home, ".config", "user-dirs.dirs"), userDirs, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewDirsFor(Linux, MapEnv(map[string]string{"HOME": filepath.ToSlash(home)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := d.Resolve(Audio)
+	if err != nil || res.Source != SourceDefault || res.Path != path.Join(filepath.ToSlash(home), "Music") {
+		t.Errorf("Expected the default Music dir without reading user-dirs.dirs, got (%v, %v)", res, err)
+	}
+}
+
+func TestNewDirsForProjectDirs(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewDirsFor(Windows, MapEnv(map[string]string{"USERPROFILE": `C:\Users\me`}))
+	if err != nil {
+		t.Fatalf("NewDirsFor returned error: %v", err)
+	}
+	p, err := ProjectDirsFrom(d, "com", "Org", "MyApp")
+	if err != nil {
+		t.Fatalf("ProjectDirsFrom returned error: %v", err)
+	}
+	path, err := p.ConfigDir()
+	if err != nil {
+		t.Fatalf("ConfigDir returned error: %v", err)
+	}
+	if expected := `C:\Users\me\AppData\Roaming\Org\MyApp`; path != expected {
+		t.Errorf("ConfigDir expected '%s', got '%s'", expected, path)
+	}
+}
+
+func TestWindowsJoin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		elem     []string
+		expected string
+	}{
+		{[]string{`C:\Users\me`, "Music"}, `C:\Users\me\Music`},
+		{[]string{`C:\`, "Users"}, `C:\Users`},
+		{[]string{`C:\Users\`, "", `Org\MyApp`}, `C:\Users\Org\MyApp`},
+		{[]string{`\\server\share`, "dir"}, `\\server\share\dir`},
+	}
+	for _, tt := range tests {
+		if got := windowsJoin(tt.elem...); got != tt.expected {
+			t.Errorf("windowsJoin(%q) expected '%s', got '%s'", tt.elem, tt.expected, got)
+		}
+	}
+}
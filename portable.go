@@ -0,0 +1,86 @@
+package dirs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DefaultPortableMarker is the marker file name used by WithPortable when
+// none is given.
+const DefaultPortableMarker = "portable.txt"
+
+// appSubdirs maps the kinds that can be kept in a single application
+// folder, in portable mode or with an $<PREFIX>_HOME override, to their
+// subfolders. DataLocal and Preference share the folders of Data and Config,
+// as they do on Linux.
+var appSubdirs = map[Kind]string{
+	Cache:      "cache",
+	Config:     "config",
+	Data:       "data",
+	DataLocal:  "data",
+	Preference: "config",
+	State:      "state",
+}
+
+// WithPortable enables portable mode for self-contained deployments, such as
+// a folder on a USB stick. If a file named marker exists beside the running
+// executable, the cache, config, data and state directories are redirected
+// to the "cache", "config", "data" and "state" folders in the executable's
+// directory; DataLocal and Preference follow Data and Config. Other kinds,
+// and all kinds when there is no marker, resolve as usual. An empty marker
+// means DefaultPortableMarker.
+//
+// The marker is looked for once, when the Dirs is created. Resolve reports
+// redirected directories with SourcePortable, and ProjectDirs uses them
+// without appending a project path, since they already belong to the
+// application. NewDirsFor ignores this option.
+func WithPortable(marker string) Option {
+	if marker == "" {
+		marker = DefaultPortableMarker
+	}
+	return func(o *options) {
+		o.portableMarker = marker
+	}
+}
+
+// portableLayout redirects the kinds in appSubdirs below root and
+// delegates everything else to the wrapped layout.
+type portableLayout struct {
+	layout
+	root   string
+	marker string // path of the marker file, for provenance
+}
+
+func (l *portableLayout) resolve(kind Kind) (Resolution, error) {
+	sub, ok := appSubdirs[kind]
+	if !ok {
+		return l.layout.resolve(kind)
+	}
+	res := Resolution{Kind: kind}
+	res.use(SourcePortable, l.marker, l.join(l.root, sub))
+	return res, nil
+}
+
+// findPortableRoot returns the directory of the running executable if it
+// contains the marker file. Symlinks to the executable are followed, so that
+// a link on the PATH finds the marker beside the real binary. Failures mean
+// the program is not portable.
+func (o *options) findPortableRoot() (root, marker string, ok bool) {
+	executable := o.executable
+	if executable == nil {
+		executable = os.Executable
+	}
+	exe, err := executable()
+	if err != nil {
+		return "", "", false
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	root = filepath.Dir(exe)
+	marker = filepath.Join(root, o.portableMarker)
+	if fi, err := os.Stat(marker); err != nil || fi.IsDir() {
+		return "", "", false
+	}
+	return root, marker, true
+}
@@ -0,0 +1,65 @@
+package dirs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withExecutable makes portable mode find the executable at exe.
+func withExecutable(exe string) Option {
+	return func(o *options) {
+		o.executable = func() (string, error) { return exe, nil }
+	}
+}
+
+func TestPortable(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	exe := filepath.Join(root, "tool")
+	if err := os.WriteFile(filepath.Join(root, DefaultPortableMarker), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	home := t.TempDir()
+	opts := []Option{
+		WithHomeDir(func() (string, error) { return home, nil }),
+		WithEnv(MapEnv(nil)),
+		withExecutable(exe),
+	}
+
+	t.Run("Marker", func(t *testing.T) {
+		d := New(append(opts, WithPortable(""))...)
+		for kind, sub := range appSubdirs {
+			res, err := d.Resolve(kind)
+			if err != nil || res.Path != filepath.Join(root, sub) || res.Source != SourcePortable {
+				t.Errorf("Expected %s from portable, got (%v, %v)", filepath.Join(root, sub), res, err)
+			}
+		}
+		if path, err := d.HomeDir(); err != nil || path != home {
+			t.Errorf("Expected HomeDir %s to be unaffected, got (%s, %v)", home, path, err)
+		}
+
+		p, err := ProjectDirsFrom(d, "com", "Org", "MyApp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path, err := p.ConfigDir(); err != nil || path != filepath.Join(root, "config") {
+			t.Errorf("Expected the project config dir to be %s, got (%s, %v)", filepath.Join(root, "config"), path, err)
+		}
+	})
+
+	t.Run("CustomMarkerMissing", func(t *testing.T) {
+		d := New(append(opts, WithPortable("portable.ini"))...)
+		if res, err := d.Resolve(Config); err != nil || res.Source == SourcePortable {
+			t.Errorf("Expected the usual config dir without the marker, got (%v, %v)", res, err)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		d := New(opts...)
+		if res, err := d.Resolve(Config); err != nil || res.Source == SourcePortable {
+			t.Errorf("Expected the usual config dir without WithPortable, got (%v, %v)", res, err)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package dirs
+
+import "fmt"
+
+// Preset selects the convention that base directories follow, independent
+// of the platform's layout.
+type Preset int
+
+const (
+	// PresetNative follows the platform's own convention, e.g.
+	// ~/Library/Application Support on macOS. It is the default.
+	PresetNative Preset = iota
+	// PresetXDG follows the XDG Base Directory spec on every platform, as
+	// git, gh and kubectl do: ~/.config, ~/.cache, ~/.local/share,
+	// ~/.local/state and ~/.local/bin, each overridable by its XDG_*
+	// variable. Application directories are named as on Linux, e.g.
+	// ~/.config/myapp. The home directory, the user's folders such as
+	// Music, and RuntimeDir keep the platform's convention.
+	PresetXDG
+	// PresetDotfile keeps everything of an application in a single
+	// directory in the user's home, e.g. ~/.myapp. The base directories
+	// Cache, Config, Data, DataLocal, Preference and State are the home
+	// directory itself, so this preset is meant to be used with ProjectDirs,
+	// whose directories are then all ~/.myapp.
+	PresetDotfile
+)
+
+var presetNames = [...]string{
+	PresetNative:  "native",
+	PresetXDG:     "xdg",
+	PresetDotfile: "dotfile",
+}
+
+func (p Preset) String() string {
+	if p < 0 || int(p) >= len(presetNames) {
+		return fmt.Sprintf("Preset(%d)", int(p))
+	}
+	return presetNames[p]
+}
+
+// WithPreset makes Dirs follow the convention of preset instead of the
+// platform's native one. An unknown preset means PresetNative.
+func WithPreset(preset Preset) Option {
+	if preset < 0 || int(preset) >= len(presetNames) {
+		preset = PresetNative
+	}
+	return func(o *options) {
+		o.preset = preset
+	}
+}
+
+// applyPreset wraps the native layout l of a platform in the layout of
+// o.preset.
+func applyPreset(l layout, o *options) layout {
+	switch o.preset {
+	case PresetXDG:
+		if l.platform() == Linux {
+			return l
+		}
+		return &xdgLayout{layout: l, xdg: &linuxDirs{options: o, host: l}}
+	case PresetDotfile:
+		return &dotfileLayout{l}
+	}
+	return l
+}
+
+// xdgLayout applies the XDG rules of linuxDirs to the base directories of
+// another platform.
+type xdgLayout struct {
+	layout
+	xdg *linuxDirs
+}
+
+func (l *xdgLayout) resolve(kind Kind) (Resolution, error) {
+	switch kind {
+	case Cache, Config, Data, DataLocal, Executable, Preference, State:
+		return l.xdg.resolve(kind)
+	}
+	return l.layout.resolve(kind)
+}
+
+// The XDG search paths are honored when set; otherwise the platform's
+// system directories are used.
+
+func (l *xdgLayout) systemConfigDirs() ([]string, error) {
+	defaults, err := l.layout.systemConfigDirs()
+	if err != nil {
+		return nil, err
+	}
+	return l.xdg.getSearchDirs("XDG_CONFIG_DIRS", defaults), nil
+}
+
+func (l *xdgLayout) systemDataDirs() ([]string, error) {
+	defaults, err := l.layout.systemDataDirs()
+	if err != nil {
+		return nil, err
+	}
+	return l.xdg.getSearchDirs("XDG_DATA_DIRS", defaults), nil
+}
+
+func (l *xdgLayout) projectPath(qualifier, organization, application string) string {
+	return xdgProjectPath(qualifier, organization, application)
+}
+
+// dotfileLayout resolves the base directories to the home directory.
+type dotfileLayout struct {
+	layout
+}
+
+func (l *dotfileLayout) resolve(kind Kind) (Resolution, error) {
+	switch kind {
+	case Cache, Config, Data, DataLocal, Preference, State:
+	default:
+		return l.layout.resolve(kind)
+	}
+	res := Resolution{Kind: kind}
+	home, err := l.layout.resolve(Home)
+	if err != nil {
+		return res, err
+	}
+	res.derive(SourceDerived, Home.String(), home.Path, home)
+	return res, nil
+}
+
+// projectPath names the application directory after the application,
+// lowercased and prefixed with a dot as is usual for dotfiles, e.g. ".myapp".
+func (l *dotfileLayout) projectPath(qualifier, organization, application string) string {
+	return "." + xdgProjectPath(qualifier, organization, application)
+}
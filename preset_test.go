@@ -0,0 +1,98 @@
+package dirs
+
+import "testing"
+
+func TestPresets(t *testing.T) {
+	darwinEnv := MapEnv(map[string]string{"HOME": "/Users/me", "XDG_CACHE_HOME": "/tmp/cache"})
+	windowsEnv := MapEnv(map[string]string{
+		"USERPROFILE":     `C:\Users\me`,
+		"APPDATA":         `C:\Users\me\AppData\Roaming`,
+		"XDG_CONFIG_HOME": `D:\config`,
+		"XDG_DATA_HOME":   "relative",
+		"PROGRAMDATA":     `C:\ProgramData`,
+	})
+	linuxEnv := MapEnv(map[string]string{"HOME": "/home/me"})
+
+	tests := []struct {
+		name     string
+		platform Platform
+		env      Env
+		preset   Preset
+		kind     Kind
+		expected string
+	}{
+		{"darwin native", Darwin, darwinEnv, PresetNative, Config, "/Users/me/Library/Application Support"},
+		{"darwin xdg", Darwin, darwinEnv, PresetXDG, Config, "/Users/me/.config"},
+		{"darwin xdg env", Darwin, darwinEnv, PresetXDG, Cache, "/tmp/cache"},
+		{"darwin xdg state", Darwin, darwinEnv, PresetXDG, State, "/Users/me/.local/state"},
+		{"darwin xdg preference", Darwin, darwinEnv, PresetXDG, Preference, "/Users/me/.config"},
+		{"darwin xdg user folder", Darwin, darwinEnv, PresetXDG, Video, "/Users/me/Movies"},
+		{"windows xdg env", Windows, windowsEnv, PresetXDG, Config, `D:\config`},
+		{"windows xdg relative env", Windows, windowsEnv, PresetXDG, Data, `C:\Users\me\.local\share`},
+		{"windows xdg home", Windows, windowsEnv, PresetXDG, Home, `C:\Users\me`},
+		{"linux xdg", Linux, linuxEnv, PresetXDG, Config, "/home/me/.config"},
+		{"linux dotfile", Linux, linuxEnv, PresetDotfile, Config, "/home/me"},
+		{"darwin dotfile", Darwin, darwinEnv, PresetDotfile, Cache, "/Users/me"},
+		{"dotfile keeps user folders", Linux, linuxEnv, PresetDotfile, Audio, "/home/me/Music"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDirsFor(tt.platform, tt.env, WithPreset(tt.preset))
+			if err != nil {
+				t.Fatal(err)
+			}
+			path, err := d.Dir(tt.kind)
+			if err != nil || path != tt.expected {
+				t.Errorf("Expected %s, got (%s, %v)", tt.expected, path, err)
+			}
+		})
+	}
+
+	t.Run("SystemDirs", func(t *testing.T) {
+		d, err := NewDirsFor(Windows, windowsEnv, WithPreset(PresetXDG))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dirs, err := d.SystemConfigDirs(); err != nil || len(dirs) != 1 || dirs[0] != `C:\ProgramData` {
+			t.Errorf("Expected the native system dirs without XDG_CONFIG_DIRS, got (%v, %v)", dirs, err)
+		}
+	})
+
+	t.Run("ProjectDirs", func(t *testing.T) {
+		tests := []struct {
+			platform Platform
+			env      Env
+			preset   Preset
+			expected string
+		}{
+			{Darwin, darwinEnv, PresetXDG, "/Users/me/.config/myapp"},
+			{Windows, windowsEnv, PresetXDG, `D:\config\myapp`},
+			{Linux, linuxEnv, PresetDotfile, "/home/me/.myapp"},
+			{Darwin, darwinEnv, PresetDotfile, "/Users/me/.myapp"},
+		}
+		for _, tt := range tests {
+			d, err := NewDirsFor(tt.platform, tt.env, WithPreset(tt.preset))
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, err := ProjectDirsFrom(d, "com", "Org", "My App")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path, err := p.ConfigDir(); err != nil || path != tt.expected {
+				t.Errorf("%s %s: expected %s, got (%s, %v)", tt.platform, tt.preset, tt.expected, path, err)
+			}
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		d, err := NewDirsFor(Darwin, darwinEnv, WithPreset(Preset(42)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path, err := d.ConfigDir(); err != nil || path != "/Users/me/Library/Application Support" {
+			t.Errorf("Expected an unknown preset to mean PresetNative, got (%s, %v)", path, err)
+		}
+		New(WithPreset(Preset(-1))) // must not panic
+	})
+}
@@ -0,0 +1,188 @@
+package dirs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ProjectDirs provides the application-specific directories for a single
+// project. The paths are the base directories of a Dirs with a project path,
+// named according to the platform's convention, appended.
+type ProjectDirs struct {
+	base      Dirs
+	layout    layout
+	path      string
+	envPrefix string
+}
+
+// ProjectOption configures the ProjectDirs returned by NewProjectDirs and
+// ProjectDirsFrom.
+type ProjectOption func(*ProjectDirs)
+
+// WithEnvPrefix lets environment variables named after prefix override the
+// application's directories without affecting other programs. With prefix
+// "MYAPP", the variables MYAPP_CACHE_DIR, MYAPP_CONFIG_DIR, MYAPP_DATA_DIR
+// and MYAPP_STATE_DIR each override one directory, and MYAPP_HOME moves all
+// four into its "cache", "config", "data" and "state" subfolders. DataLocal
+// and Preference follow Data and Config. A specific variable takes
+// precedence over MYAPP_HOME, which takes precedence over the base
+// directories. Variables are read from the environment of the base Dirs, or
+// from the process environment if it was not returned by this package, and
+// ones that are not absolute paths are ignored.
+func WithEnvPrefix(prefix string) ProjectOption {
+	return func(p *ProjectDirs) {
+		p.envPrefix = strings.TrimSuffix(prefix, "_")
+	}
+}
+
+// NewProjectDirs returns the directories for the application identified by
+// qualifier (e.g. "com"), organization and application, using the base
+// directories returned by NewDirs.
+func NewProjectDirs(qualifier, organization, application string, opts ...ProjectOption) (*ProjectDirs, error) {
+	return ProjectDirsFrom(NewDirs(), qualifier, organization, application, opts...)
+}
+
+// ProjectDirsFrom is like NewProjectDirs but derives the directories from d.
+// If d was not returned by this package, the project path is named and
+// joined following the conventions of the current platform.
+func ProjectDirsFrom(d Dirs, qualifier, organization, application string, opts ...ProjectOption) (*ProjectDirs, error) {
+	if strings.TrimSpace(application) == "" {
+		return nil, errors.New("dirs: application name must not be empty")
+	}
+	// Dirs implemented outside this package follow the host's conventions.
+	ld, ok := d.(*layoutDirs)
+	if !ok {
+		ld = NewDirs().(*layoutDirs)
+	}
+	l := ld.layout
+	p := &ProjectDirs{
+		base:   d,
+		layout: l,
+		path:   l.projectPath(strings.TrimSpace(qualifier), strings.TrimSpace(organization), strings.TrimSpace(application)),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// ProjectPath returns the project path that is appended to each base
+// directory, e.g. "myapp", "com.Org.MyApp" or `Org\MyApp`.
+func (p *ProjectDirs) ProjectPath() string {
+	return p.path
+}
+
+// Dir returns the application directory of the given kind. Only the base
+// directories (Cache, Config, Data, DataLocal, Preference, Runtime and State)
+// have application directories; other kinds report ErrNotSupported.
+func (p *ProjectDirs) Dir(kind Kind) (string, error) {
+	res, err := p.Resolve(kind)
+	return res.Path, err
+}
+
+// Resolve is like Dir but also explains where the directory came from. The
+// returned Resolution is derived from the base directory's resolution.
+func (p *ProjectDirs) Resolve(kind Kind) (Resolution, error) {
+	res := Resolution{Kind: kind}
+	switch kind {
+	case Cache, Config, Data, DataLocal, Preference, Runtime, State:
+	default:
+		if !kind.valid() {
+			return res, &DirError{Kind: kind, Err: fmt.Errorf("unknown directory kind")}
+		}
+		return res, &DirError{Kind: kind, Err: ErrNotSupported}
+	}
+	if p.lookupEnvOverride(&res) {
+		return res, nil
+	}
+	base, err := p.base.Resolve(kind)
+	// An empty base directory means the platform has no such directory, so
+	// the result stays empty.
+	if err != nil || base.Path == "" {
+		return base, err
+	}
+	// Portable directories already belong to the application.
+	if base.Source == SourcePortable {
+		return base, nil
+	}
+	res.derive(SourceDerived, p.layout.join(kind.String(), p.path), p.layout.join(base.Path, p.path), base)
+	return res, nil
+}
+
+// lookupEnvOverride records the override of res.Kind set with WithEnvPrefix,
+// if any. Unset variables and relative paths are recorded as skipped
+// candidates, so that Check reports the latter.
+func (p *ProjectDirs) lookupEnvOverride(res *Resolution) bool {
+	sub, ok := appSubdirs[res.Kind]
+	if p.envPrefix == "" || !ok {
+		return false
+	}
+	envVar := p.envPrefix + "_" + strings.ToUpper(sub) + "_DIR"
+	if dir, ok := p.lookupOverrideVar(res, envVar); ok {
+		res.use(SourceEnv, envVar, dir)
+		return true
+	}
+	homeVar := p.envPrefix + "_HOME"
+	if dir, ok := p.lookupOverrideVar(res, homeVar); ok {
+		res.use(SourceEnv, homeVar, p.layout.join(dir, sub))
+		return true
+	}
+	return false
+}
+
+// lookupOverrideVar returns the value of envVar if it is an absolute path,
+// and otherwise records it as skipped in res.
+func (p *ProjectDirs) lookupOverrideVar(res *Resolution, envVar string) (string, bool) {
+	dir := p.layout.getenv(envVar)
+	switch {
+	case dir == "":
+		res.skip(SourceEnv, envVar, "", "not set")
+		return "", false
+	case !p.layout.isAbs(dir):
+		res.skip(SourceEnv, envVar, dir, "relative path ignored")
+		return "", false
+	}
+	return dir, true
+}
+
+// EnsureDir is like Dir but also creates the application directory, as
+// described for Dirs.EnsureDir.
+func (p *ProjectDirs) EnsureDir(kind Kind) (string, error) {
+	return ensureDir(p, kind)
+}
+
+func (p *ProjectDirs) CacheDir() (string, error)      { return p.Dir(Cache) }
+func (p *ProjectDirs) ConfigDir() (string, error)     { return p.Dir(Config) }
+func (p *ProjectDirs) DataDir() (string, error)       { return p.Dir(Data) }
+func (p *ProjectDirs) DataLocalDir() (string, error)  { return p.Dir(DataLocal) }
+func (p *ProjectDirs) PreferenceDir() (string, error) { return p.Dir(Preference) }
+func (p *ProjectDirs) RuntimeDir() (string, error)    { return p.Dir(Runtime) }
+func (p *ProjectDirs) StateDir() (string, error)      { return p.Dir(State) }
+
+// xdgProjectPath follows the XDG convention: the lowercased application name
+// without whitespace, e.g. "myapp".
+func xdgProjectPath(qualifier, organization, application string) string {
+	return strings.ToLower(strings.Join(strings.Fields(application), ""))
+}
+
+// macProjectPath follows the macOS bundle identifier convention, e.g.
+// "com.Org.MyApp". Whitespace within a component is replaced by hyphens.
+func macProjectPath(qualifier, organization, application string) string {
+	var parts []string
+	for _, part := range []string{qualifier, organization, application} {
+		if part != "" {
+			parts = append(parts, strings.Join(strings.Fields(part), "-"))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// windowsProjectPath follows the Windows convention of nesting the
+// application under its organization, e.g. `Org\MyApp`.
+func windowsProjectPath(qualifier, organization, application string) string {
+	if organization == "" {
+		return application
+	}
+	return organization + `\` + application
+}
@@ -0,0 +1,174 @@
+package dirs
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProjectPathConventions(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       func(qualifier, organization, application string) string
+		args     [3]string
+		expected string
+	}{
+		{"xdg", xdgProjectPath, [3]string{"com", "Org", "MyApp"}, "myapp"},
+		{"xdg spaces", xdgProjectPath, [3]string{"com", "Org", "My App"}, "myapp"},
+		{"mac", macProjectPath, [3]string{"com", "Org", "MyApp"}, "com.Org.MyApp"},
+		{"mac spaces", macProjectPath, [3]string{"com", "Big Org", "My App"}, "com.Big-Org.My-App"},
+		{"mac no qualifier", macProjectPath, [3]string{"", "Org", "MyApp"}, "Org.MyApp"},
+		{"windows", windowsProjectPath, [3]string{"com", "Org", "MyApp"}, `Org\MyApp`},
+		{"windows no organization", windowsProjectPath, [3]string{"com", "", "MyApp"}, "MyApp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.fn(tt.args[0], tt.args[1], tt.args[2])
+			if got != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProjectDirs(t *testing.T) {
+	if _, err := NewProjectDirs("com", "Org", " "); err == nil {
+		t.Error("Expected an error for an empty application name")
+	}
+
+	d := NewDirs()
+	p, err := ProjectDirsFrom(d, "com", "Org", "MyApp")
+	if err != nil {
+		t.Fatalf("ProjectDirsFrom returned error: %v", err)
+	}
+
+	tests := map[string]struct {
+		project func() (string, error)
+		base    func() (string, error)
+	}{
+		"CacheDir":      {p.CacheDir, d.CacheDir},
+		"ConfigDir":     {p.ConfigDir, d.ConfigDir},
+		"DataDir":       {p.DataDir, d.DataDir},
+		"DataLocalDir":  {p.DataLocalDir, d.DataLocalDir},
+		"PreferenceDir": {p.PreferenceDir, d.PreferenceDir},
+		"RuntimeDir":    {p.RuntimeDir, d.RuntimeDir},
+		"StateDir":      {p.StateDir, d.StateDir},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			base, baseErr := tt.base()
+			path, err := tt.project()
+			if baseErr != nil {
+				// Unsupported base directories are unsupported for projects too.
+				if err == nil || err.Error() != baseErr.Error() {
+					t.Errorf("project %s expected error %v, got %v", name, baseErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("project %s returned error: %v", name, err)
+			}
+			expected := filepath.Join(base, p.ProjectPath())
+			if path != expected {
+				t.Errorf("%s expected '%s', got '%s'", name, expected, path)
+			}
+		})
+	}
+
+	t.Run("UnknownKind", func(t *testing.T) {
+		_, err := p.Dir(Kind(0))
+		_, baseErr := d.Dir(Kind(0))
+		if err == nil || errors.Is(err, ErrNotSupported) || err.Error() != baseErr.Error() {
+			t.Errorf("Expected %v, got %v", baseErr, err)
+		}
+	})
+}
+
+func TestProjectDirsEnvPrefix(t *testing.T) {
+	d, err := NewDirsFor(Linux, MapEnv(map[string]string{
+		"HOME":             "/home/me",
+		"MYAPP_CACHE_DIR":  "/var/cache/myapp",
+		"MYAPP_HOME":       "/opt/myapp",
+		"OTHER_CONFIG_DIR": "/other",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := ProjectDirsFrom(d, "com", "Org", "MyApp", WithEnvPrefix("MYAPP_"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		kind     Kind
+		expected string
+		detail   string
+	}{
+		{Cache, "/var/cache/myapp", "MYAPP_CACHE_DIR"},
+		{Config, "/opt/myapp/config", "MYAPP_HOME"},
+		{Preference, "/opt/myapp/config", "MYAPP_HOME"},
+		{State, "/opt/myapp/state", "MYAPP_HOME"},
+		{Runtime, "", ""}, // not overridable, and unset here
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind.String(), func(t *testing.T) {
+			res, err := p.Resolve(tt.kind)
+			if tt.detail == "" {
+				if res.Source == SourceEnv {
+					t.Errorf("Expected no override, got %v", res)
+				}
+				return
+			}
+			if err != nil || res.Path != tt.expected || res.Source != SourceEnv || res.Detail != tt.detail {
+				t.Errorf("Expected %s from env %s, got (%v, %v)", tt.expected, tt.detail, res, err)
+			}
+		})
+	}
+
+	t.Run("Unset", func(t *testing.T) {
+		p, err := ProjectDirsFrom(d, "com", "Org", "MyApp", WithEnvPrefix("OTHER"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := p.Resolve(Data)
+		if err != nil || res.Path != "/home/me/.local/share/myapp" {
+			t.Fatalf("Expected the derived data dir, got (%v, %v)", res, err)
+		}
+		if len(res.Skipped) != 2 || res.Skipped[0].Detail != "OTHER_DATA_DIR" || res.Skipped[1].Detail != "OTHER_HOME" {
+			t.Errorf("Expected the unset overrides to be recorded, got %v", res.Skipped)
+		}
+	})
+
+	t.Run("Relative", func(t *testing.T) {
+		d, err := NewDirsFor(Linux, MapEnv(map[string]string{
+			"HOME":            "/home/me",
+			"MYAPP_DATA_DIR":  "data",
+			"MYAPP_HOME":      "/opt/myapp",
+			"MYAPP_CACHE_DIR": "cache",
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := ProjectDirsFrom(d, "com", "Org", "MyApp", WithEnvPrefix("MYAPP"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := p.Resolve(Data)
+		if err != nil || res.Path != "/opt/myapp/data" || res.Detail != "MYAPP_HOME" {
+			t.Fatalf("Expected MYAPP_HOME to apply, got (%v, %v)", res, err)
+		}
+		if len(res.Skipped) != 1 || res.Skipped[0].Value != "data" || res.Skipped[0].Reason != "relative path ignored" {
+			t.Errorf("Expected the relative override to be recorded, got %v", res.Skipped)
+		}
+		var found bool
+		findings := Check(p, Cache)
+		for _, f := range findings {
+			found = found || strings.Contains(f.Message, "MYAPP_CACHE_DIR")
+		}
+		if !found {
+			t.Errorf("Expected Check to report MYAPP_CACHE_DIR, got %v", findings)
+		}
+	})
+}
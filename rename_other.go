@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package dirs
+
+// crossDevice reports whether err is a rename failing because the source
+// and destination are on different filesystems. This platform does not
+// report it distinctly, so such renames simply fail.
+func crossDevice(err error) bool {
+	return false
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package dirs
+
+import (
+	"errors"
+	"syscall"
+)
+
+// crossDevice reports whether err is a rename failing because the source
+// and destination are on different filesystems.
+func crossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
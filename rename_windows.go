@@ -0,0 +1,18 @@
+//go:build windows
+
+package dirs
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errorNotSameDevice is ERROR_NOT_SAME_DEVICE, returned by MoveFileEx for
+// moves between volumes.
+const errorNotSameDevice syscall.Errno = 17
+
+// crossDevice reports whether err is a rename failing because the source
+// and destination are on different volumes.
+func crossDevice(err error) bool {
+	return errors.Is(err, errorNotSameDevice)
+}
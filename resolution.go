@@ -0,0 +1,124 @@
+package dirs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Source describes where a resolved directory came from.
+type Source int
+
+const (
+	// SourceNone means the directory was not resolved, e.g. because it is
+	// unsupported on the platform.
+	SourceNone Source = iota
+	// SourceEnv means the directory was read from an environment variable.
+	SourceEnv
+	// SourceFile means the directory was read from a configuration file,
+	// such as user-dirs.dirs.
+	SourceFile
+	// SourceDefault means the directory is a built-in default, usually
+	// relative to the home directory.
+	SourceDefault
+	// SourceOverride means the directory was set explicitly by the program,
+	// e.g. with WithHomeDir.
+	SourceOverride
+	// SourceDerived means the directory was computed from another directory,
+	// e.g. FontDir from DataDir.
+	SourceDerived
+	// SourceFallback means the preferred sources were unusable and a
+	// substitute was used, e.g. with WithRuntimeFallback.
+	SourceFallback
+	// SourcePortable means the directory is beside the executable because
+	// portable mode is enabled with WithPortable; Detail is the marker file.
+	SourcePortable
+)
+
+var sourceNames = [...]string{
+	SourceNone:     "none",
+	SourceEnv:      "env",
+	SourceFile:     "file",
+	SourceDefault:  "default",
+	SourceOverride: "override",
+	SourceDerived:  "derived",
+	SourceFallback: "fallback",
+	SourcePortable: "portable",
+}
+
+func (s Source) String() string {
+	if s < 0 || int(s) >= len(sourceNames) {
+		return fmt.Sprintf("Source(%d)", int(s))
+	}
+	return sourceNames[s]
+}
+
+// Resolution explains how a directory was resolved.
+type Resolution struct {
+	Kind   Kind
+	Path   string
+	Source Source
+	// Detail identifies the source: the environment variable name for
+	// SourceEnv, "file:line" for SourceFile, and a short description of the
+	// rule for the other sources, e.g. "$HOME/.cache".
+	Detail string
+	// Base is the resolution Path was computed from, if any, such as the
+	// home directory for a default under $HOME.
+	Base *Resolution
+	// Skipped lists the higher-precedence candidates that were considered
+	// and rejected, in the order they were tried.
+	Skipped []Candidate
+}
+
+// Candidate is a possible source for a directory that was not used.
+type Candidate struct {
+	Source Source
+	Detail string // as for Resolution.Detail
+	Value  string // the rejected value, if there was one
+	Reason string // why it was rejected, e.g. "not set"
+}
+
+func (c Candidate) String() string {
+	s := c.Source.String() + " " + c.Detail
+	if c.Value != "" {
+		s += fmt.Sprintf(" (%q)", c.Value)
+	}
+	return s + ": " + c.Reason
+}
+
+// String summarizes the resolution on a single line, e.g.
+// "cache: /home/me/.cache from default $HOME/.cache; skipped env XDG_CACHE_HOME: not set".
+func (r Resolution) String() string {
+	var b strings.Builder
+	b.WriteString(r.Kind.String())
+	b.WriteString(": ")
+	if r.Source == SourceNone {
+		b.WriteString("unresolved")
+	} else {
+		fmt.Fprintf(&b, "%s from %s %s", r.Path, r.Source, r.Detail)
+	}
+	for i, c := range r.Skipped {
+		if i == 0 {
+			b.WriteString("; skipped ")
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(c.String())
+	}
+	return b.String()
+}
+
+// skip records a rejected candidate.
+func (r *Resolution) skip(source Source, detail, value, reason string) {
+	r.Skipped = append(r.Skipped, Candidate{Source: source, Detail: detail, Value: value, Reason: reason})
+}
+
+// use records the candidate that was selected.
+func (r *Resolution) use(source Source, detail, path string) {
+	r.Source, r.Detail, r.Path = source, detail, path
+}
+
+// derive records path as computed from base.
+func (r *Resolution) derive(source Source, detail, path string, base Resolution) {
+	r.use(source, detail, path)
+	r.Base = &base
+}
@@ -0,0 +1,191 @@
+package dirs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveLinux(t *testing.T) {
+	t.Parallel()
+
+	home := filepath.ToSlash(t.TempDir())
+	configDir := home + "/.config"
+	if err := os.MkdirAll(configDir, 0o700); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	contents := "# comment\nXDG_MUSIC_DIR=\"$HOME/Musik\"\n"
+	if err := os.WriteFile(configDir+"/user-dirs.dirs", []byte(contents), 0o600); err != nil {
+		t.Fatalf("Failed to write user-dirs.dirs: %v", err)
+	}
+
+	// The Linux layout as used on a Linux host, which unlike NewDirsFor
+	// reads user-dirs.dirs.
+	d, err := newLayoutDirs(Linux, newOptions([]Option{WithEnv(MapEnv(map[string]string{
+		"HOME":           home,
+		"XDG_CACHE_HOME": "/tmp/cache",
+	}))}))
+	if err != nil {
+		t.Fatalf("newLayoutDirs returned error: %v", err)
+	}
+
+	t.Run("Env", func(t *testing.T) {
+		res, err := d.Resolve(Cache)
+		if err != nil {
+			t.Fatalf("Resolve returned error: %v", err)
+		}
+		if res.Path != "/tmp/cache" || res.Source != SourceEnv || res.Detail != "XDG_CACHE_HOME" {
+			t.Errorf("Unexpected resolution: %s", res)
+		}
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		res, err := d.Resolve(State)
+		if err != nil {
+			t.Fatalf("Resolve returned error: %v", err)
+		}
+		if res.Source != SourceDefault || res.Detail != "$HOME/.local/state" || res.Path != home+"/.local/state" {
+			t.Errorf("Unexpected resolution: %s", res)
+		}
+		if res.Base == nil || res.Base.Kind != Home || res.Base.Source != SourceEnv || res.Base.Detail != "HOME" {
+			t.Errorf("Expected the home directory from $HOME as base, got %+v", res.Base)
+		}
+		if len(res.Skipped) != 1 || res.Skipped[0].Detail != "XDG_STATE_HOME" || res.Skipped[0].Reason != "not set" {
+			t.Errorf("Expected XDG_STATE_HOME to be skipped, got %v", res.Skipped)
+		}
+	})
+
+	t.Run("File", func(t *testing.T) {
+		res, err := d.Resolve(Audio)
+		if err != nil {
+			t.Fatalf("Resolve returned error: %v", err)
+		}
+		if res.Source != SourceFile || res.Path != home+"/Musik" || res.Detail != configDir+"/user-dirs.dirs:2" {
+			t.Errorf("Unexpected resolution: %s", res)
+		}
+	})
+
+	t.Run("FileWithoutEntry", func(t *testing.T) {
+		res, err := d.Resolve(Video)
+		if err != nil {
+			t.Fatalf("Resolve returned error: %v", err)
+		}
+		if res.Source != SourceDefault || len(res.Skipped) != 2 || res.Skipped[1].Source != SourceFile {
+			t.Errorf("Expected the env var and file to be skipped, got: %s", res)
+		}
+	})
+
+	t.Run("Derived", func(t *testing.T) {
+		res, err := d.Resolve(Font)
+		if err != nil {
+			t.Fatalf("Resolve returned error: %v", err)
+		}
+		if res.Source != SourceDerived || res.Detail != "data/fonts" || res.Base == nil || res.Base.Kind != Data {
+			t.Errorf("Unexpected resolution: %s", res)
+		}
+	})
+
+	t.Run("NotSet", func(t *testing.T) {
+		res, err := d.Resolve(Runtime)
+		if !errors.Is(err, ErrNotSet) {
+			t.Errorf("Expected ErrNotSet, got %v", err)
+		}
+		if res.Source != SourceNone || len(res.Skipped) != 1 || res.Skipped[0].Detail != "XDG_RUNTIME_DIR" {
+			t.Errorf("Unexpected resolution: %s", res)
+		}
+	})
+}
+
+func TestResolveOverride(t *testing.T) {
+	t.Parallel()
+
+	d := New(WithHomeDir(func() (string, error) { return "/override", nil }))
+	res, err := d.Resolve(Home)
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if res.Source != SourceOverride || res.Path != "/override" {
+		t.Errorf("Unexpected resolution: %s", res)
+	}
+}
+
+func TestResolveOtherPlatforms(t *testing.T) {
+	t.Parallel()
+
+	darwin, err := NewDirsFor(Darwin, MapEnv(map[string]string{"HOME": "/Users/me"}))
+	if err != nil {
+		t.Fatalf("NewDirsFor returned error: %v", err)
+	}
+	res, err := darwin.Resolve(Preference)
+	if err != nil || res.Source != SourceDefault || res.Detail != "$HOME/Library/Preferences" {
+		t.Errorf("Unexpected darwin resolution: %s (%v)", res, err)
+	}
+
+	windows, err := NewDirsFor(Windows, MapEnv(map[string]string{"USERPROFILE": `C:\Users\me`}))
+	if err != nil {
+		t.Fatalf("NewDirsFor returned error: %v", err)
+	}
+	res, err = windows.Resolve(Config)
+	if err != nil || res.Source != SourceDefault || res.Detail != `%USERPROFILE%\AppData\Roaming` {
+		t.Errorf("Unexpected windows resolution: %s (%v)", res, err)
+	}
+	if len(res.Skipped) != 1 || res.Skipped[0].Detail != "APPDATA" {
+		t.Errorf("Expected APPDATA to be skipped, got %v", res.Skipped)
+	}
+}
+
+func TestResolutionString(t *testing.T) {
+	res := Resolution{Kind: Cache, Path: "/home/me/.cache", Source: SourceDefault, Detail: "$HOME/.cache"}
+	res.skip(SourceEnv, "XDG_CACHE_HOME", "", "not set")
+	expected := "cache: /home/me/.cache from default $HOME/.cache; skipped env XDG_CACHE_HOME: not set"
+	if s := res.String(); s != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, s)
+	}
+	if !strings.Contains(Resolution{Kind: Runtime}.String(), "unresolved") {
+		t.Error("Expected an unresolved resolution to say so")
+	}
+}
+
+func TestResolveRelativeXDG(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewDirsFor(Linux, MapEnv(map[string]string{
+		"HOME":            "/home/me",
+		"XDG_CONFIG_HOME": ".config",
+		"XDG_MUSIC_DIR":   "Music",
+		"XDG_RUNTIME_DIR": "run",
+	}))
+	if err != nil {
+		t.Fatalf("NewDirsFor returned error: %v", err)
+	}
+
+	res, err := d.Resolve(Config)
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if res.Path != "/home/me/.config" || res.Source != SourceDefault {
+		t.Errorf("Expected the relative XDG_CONFIG_HOME to be ignored, got %s", res)
+	}
+	if len(res.Skipped) != 1 || res.Skipped[0].Value != ".config" || !strings.Contains(res.Skipped[0].Reason, "relative") {
+		t.Errorf("Expected the rejected value to be recorded, got %v", res.Skipped)
+	}
+
+	if path, err := d.AudioDir(); err != nil || path != "/home/me/Music" {
+		t.Errorf("Expected the relative XDG_MUSIC_DIR to be ignored, got (%s, %v)", path, err)
+	}
+
+	_, err = d.RuntimeDir()
+	if !errors.Is(err, ErrNotSet) || !strings.Contains(err.Error(), "relative path") {
+		t.Errorf("Expected ErrNotSet for a relative XDG_RUNTIME_DIR, got %v", err)
+	}
+
+	// Check reports the rejection, including for derived directories.
+	for _, k := range []Kind{Config, Preference} {
+		findings := Check(d, k)
+		if len(findings) == 0 || findings[0].Severity != SeverityWarning || !strings.Contains(findings[0].Message, "XDG_CONFIG_HOME") {
+			t.Errorf("Expected a warning about XDG_CONFIG_HOME for %s, got %v", k, findings)
+		}
+	}
+}
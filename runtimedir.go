@@ -0,0 +1,68 @@
+package dirs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+)
+
+// WithRuntimeFallback makes the Linux RuntimeDir fall back to a private
+// directory when XDG_RUNTIME_DIR is unset or fails validation, as happens
+// in SSH sessions and containers without a login manager. The fallback is
+// $TMPDIR/dirs-runtime-<uid> (or /tmp/dirs-runtime-<uid>), created with mode
+// 0700 if missing and rejected if it exists with other ownership or mode.
+// Resolve reports it with SourceFallback.
+func WithRuntimeFallback() Option {
+	return func(o *options) {
+		o.runtimeFallback = true
+	}
+}
+
+// validateRuntimeDir checks the requirements of the XDG Base Directory spec:
+// the runtime directory must be a directory owned by the user, with mode
+// 0700, on a local filesystem.
+func validateRuntimeDir(dir string, uid int) error {
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return errors.New("not a directory")
+	}
+	if owner, ok := fileUID(fi); ok && owner != uid {
+		return fmt.Errorf("%w: owned by uid %d, not %d", ErrInsecure, owner, uid)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o700 {
+		return fmt.Errorf("%w: mode %04o, not 0700", ErrInsecure, perm)
+	}
+	if fsType := networkFilesystem(dir); fsType != "" {
+		return fmt.Errorf("on a %s network filesystem", fsType)
+	}
+	return nil
+}
+
+// runtimeFallback returns the private fallback runtime directory for uid
+// under tmpDir, creating it if necessary.
+func runtimeFallback(tmpDir string, uid int) (string, error) {
+	dir := path.Join(tmpDir, "dirs-runtime-"+strconv.Itoa(uid))
+	err := os.Mkdir(dir, 0o700)
+	switch {
+	case err == nil:
+		// Mkdir is subject to the umask, which cannot widen 0700, but be
+		// explicit in case the directory was created with a narrower mode.
+		if err := os.Chmod(dir, 0o700); err != nil {
+			return "", err
+		}
+	case !errors.Is(err, fs.ErrExist):
+		return "", err
+	}
+	// Whether just created or left by an earlier run, never trust a
+	// directory in a world-writable location without checking it.
+	if err := validateRuntimeDir(dir, uid); err != nil {
+		return "", fmt.Errorf("%s: %w", dir, err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,34 @@
+package dirs
+
+import "os"
+
+// WithSecureMode forces secure mode, which is otherwise enabled
+// automatically on Linux when the process runs setuid or setgid, i.e. when
+// its real and effective uid or gid differ, matching the semantics of
+// secure_getenv(3). In secure mode every environment variable, including
+// HOME, the XDG_* variables and any given with WithEnv, is ignored so that
+// an unprivileged caller cannot redirect privileged writes. The home
+// directory is then read from the passwd entry of the effective user and
+// the other directories take their defaults below it; WithHomeDir still
+// applies. It has no effect on other platforms or with NewDirsFor.
+func WithSecureMode() Option {
+	return func(o *options) {
+		o.secure = true
+	}
+}
+
+// privileged reports whether the process runs with privileges its caller
+// does not have, as a setuid or setgid program does.
+func privileged() bool {
+	return os.Getuid() != os.Geteuid() || os.Getgid() != os.Getegid()
+}
+
+// applySecureMode enables secure mode for the Linux layout if it is forced or
+// the process is privileged.
+func applySecureMode(p Platform, o *options) {
+	if p != Linux || o.pure || !(o.secure || privileged()) {
+		return
+	}
+	o.secure = true
+	o.env = MapEnv(nil)
+}
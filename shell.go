@@ -0,0 +1,110 @@
+package dirs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Shell identifies a shell syntax for WriteExports.
+type Shell int
+
+const (
+	// POSIX is the syntax of sh, bash, zsh and other Bourne-style shells.
+	POSIX Shell = iota + 1
+	// Fish is the syntax of the fish shell.
+	Fish
+	// PowerShell is the syntax of Windows PowerShell and pwsh.
+	PowerShell
+)
+
+var shellNames = [...]string{
+	POSIX:      "posix",
+	Fish:       "fish",
+	PowerShell: "powershell",
+}
+
+func (s Shell) String() string {
+	if s < POSIX || s > PowerShell {
+		return fmt.Sprintf("Shell(%d)", int(s))
+	}
+	return shellNames[s]
+}
+
+// ParseShell returns the shell syntax for name, which may be a shell's name
+// ("sh", "bash", "zsh", "fish", "pwsh", ...) or one of the String values.
+func ParseShell(name string) (Shell, error) {
+	switch strings.ToLower(name) {
+	case "posix", "sh", "bash", "zsh", "dash", "ksh":
+		return POSIX, nil
+	case "fish":
+		return Fish, nil
+	case "powershell", "pwsh":
+		return PowerShell, nil
+	}
+	return 0, fmt.Errorf("dirs: unknown shell %q", name)
+}
+
+// Assign returns a statement that sets the environment variable name to
+// value for child processes, quoted so that value is taken literally.
+func (s Shell) Assign(name, value string) string {
+	switch s {
+	case Fish:
+		quoted := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+		return "set -gx " + name + " '" + quoted + "'"
+	case PowerShell:
+		return "$env:" + name + " = '" + strings.ReplaceAll(value, "'", "''") + "'"
+	default:
+		return "export " + name + "='" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+	}
+}
+
+// EnvName returns the variable name used for kind in exports and KEY=value
+// listings, e.g. "CONFIG_DIR" or "DATA_LOCAL_DIR".
+func (k Kind) EnvName() string {
+	return strings.ToUpper(k.String()) + "_DIR"
+}
+
+// WriteExports writes one assignment per directory kind in the syntax of sh,
+// so that the output can be evaluated by that shell. Each variable is named
+// prefix followed by Kind.EnvName, e.g. "MYAPP_CONFIG_DIR" for the prefix
+// "MYAPP_". Directories that are unsupported or unset on the platform are
+// left out; any other error is returned.
+func WriteExports(w io.Writer, l Locator, sh Shell, prefix string) error {
+	if sh < POSIX || sh > PowerShell {
+		return fmt.Errorf("dirs: invalid shell %v", sh)
+	}
+	if !isEnvName(prefix + "X") {
+		return fmt.Errorf("dirs: invalid variable prefix %q", prefix)
+	}
+	for _, k := range Kinds() {
+		path, err := l.Dir(k)
+		if errors.Is(err, ErrNotSupported) || errors.Is(err, ErrNotSet) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if path == "" {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, sh.Assign(prefix+k.EnvName(), path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isEnvName reports whether name is a portable environment variable name.
+func isEnvName(name string) bool {
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
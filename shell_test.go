@@ -0,0 +1,78 @@
+package dirs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestShellAssign(t *testing.T) {
+	value := `/home/o'neil/My $Dir\x`
+	tests := []struct {
+		shell    Shell
+		expected string
+	}{
+		{POSIX, `export NAME='/home/o'\''neil/My $Dir\x'`},
+		{Fish, `set -gx NAME '/home/o\'neil/My $Dir\\x'`},
+		{PowerShell, `$env:NAME = '/home/o''neil/My $Dir\x'`},
+	}
+	for _, tt := range tests {
+		if got := tt.shell.Assign("NAME", value); got != tt.expected {
+			t.Errorf("%v: expected %s, got %s", tt.shell, tt.expected, got)
+		}
+	}
+}
+
+func TestParseShell(t *testing.T) {
+	for name, expected := range map[string]Shell{"bash": POSIX, "ZSH": POSIX, "fish": Fish, "pwsh": PowerShell, "powershell": PowerShell} {
+		if sh, err := ParseShell(name); err != nil || sh != expected {
+			t.Errorf("ParseShell(%q) expected %v, got (%v, %v)", name, expected, sh, err)
+		}
+	}
+	if _, err := ParseShell("cmd"); err == nil {
+		t.Error("Expected an error for an unknown shell")
+	}
+}
+
+func TestWriteExports(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewDirsFor(Darwin, MapEnv(map[string]string{"HOME": "/Users/Jane Doe"}))
+	if err != nil {
+		t.Fatalf("NewDirsFor returned error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteExports(&buf, d, POSIX, "APP_"); err != nil {
+		t.Fatalf("WriteExports returned error: %v", err)
+	}
+	expected := `export APP_HOME_DIR='/Users/Jane Doe'
+export APP_CACHE_DIR='/Users/Jane Doe/Library/Caches'
+export APP_CONFIG_DIR='/Users/Jane Doe/Library/Application Support'
+export APP_DATA_DIR='/Users/Jane Doe/Library/Application Support'
+export APP_DATA_LOCAL_DIR='/Users/Jane Doe/Library/Application Support'
+export APP_PREFERENCE_DIR='/Users/Jane Doe/Library/Preferences'
+export APP_AUDIO_DIR='/Users/Jane Doe/Music'
+export APP_DESKTOP_DIR='/Users/Jane Doe/Desktop'
+export APP_DOCUMENT_DIR='/Users/Jane Doe/Documents'
+export APP_DOWNLOAD_DIR='/Users/Jane Doe/Downloads'
+export APP_FONT_DIR='/Users/Jane Doe/Library/Fonts'
+export APP_PICTURE_DIR='/Users/Jane Doe/Pictures'
+export APP_PUBLIC_DIR='/Users/Jane Doe/Public'
+export APP_VIDEO_DIR='/Users/Jane Doe/Movies'
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected exports:\n%s", buf.String())
+	}
+
+	if err := WriteExports(&buf, d, POSIX, "1BAD"); err == nil {
+		t.Error("Expected an error for an invalid prefix")
+	}
+
+	noHome, err := NewDirsFor(Linux, MapEnv(nil))
+	if err != nil {
+		t.Fatalf("NewDirsFor returned error: %v", err)
+	}
+	if err := WriteExports(&buf, noHome, Fish, ""); !errors.Is(err, ErrHomeNotFound) {
+		t.Errorf("Expected ErrHomeNotFound, got %v", err)
+	}
+}
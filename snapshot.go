@@ -0,0 +1,117 @@
+package dirs
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Snapshot records every directory of a Dirs at one point in time. It is a
+// value type: copies are independent and a Snapshot never changes after
+// Capture, so it can be logged, serialized and compared later.
+type Snapshot struct {
+	entries [Video + 1]snapshotEntry
+}
+
+type snapshotEntry struct {
+	Path string `json:"path,omitempty"`
+	Err  string `json:"error,omitempty"`
+}
+
+// Capture resolves every kind with d.Dir and records the results. Errors are
+// recorded per kind rather than returned, since some directories are
+// legitimately unavailable on some platforms.
+func Capture(d Dirs) Snapshot {
+	var s Snapshot
+	for _, k := range Kinds() {
+		path, err := d.Dir(k)
+		s.entries[k].Path = path
+		if err != nil {
+			s.entries[k].Err = err.Error()
+		}
+	}
+	return s
+}
+
+// Path returns the recorded directory of kind, or an empty string if it
+// could not be resolved.
+func (s Snapshot) Path(kind Kind) string {
+	if !kind.valid() {
+		return ""
+	}
+	return s.entries[kind].Path
+}
+
+// Err returns the recorded error message for kind, or an empty string if it
+// was resolved successfully.
+func (s Snapshot) Err(kind Kind) string {
+	if !kind.valid() {
+		return ""
+	}
+	return s.entries[kind].Err
+}
+
+// Equal reports whether s and other recorded the same directories and errors.
+func (s Snapshot) Equal(other Snapshot) bool {
+	return s.entries == other.entries
+}
+
+// Change describes how one directory differs between two snapshots.
+type Change struct {
+	Kind   Kind
+	Old    string // path in the first snapshot
+	New    string // path in the second snapshot
+	OldErr string // error in the first snapshot
+	NewErr string // error in the second snapshot
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Kind, describeEntry(c.Old, c.OldErr), describeEntry(c.New, c.NewErr))
+}
+
+func describeEntry(path, err string) string {
+	if err != "" {
+		return "error(" + err + ")"
+	}
+	if path == "" {
+		return `""`
+	}
+	return path
+}
+
+// Diff returns the directories that differ between s and other, in the order
+// of Kinds. The Old fields describe s and the New fields describe other.
+func (s Snapshot) Diff(other Snapshot) []Change {
+	var changes []Change
+	for _, k := range Kinds() {
+		a, b := s.entries[k], other.entries[k]
+		if a != b {
+			changes = append(changes, Change{Kind: k, Old: a.Path, New: b.Path, OldErr: a.Err, NewErr: b.Err})
+		}
+	}
+	return changes
+}
+
+// MarshalJSON encodes the snapshot as an object keyed by kind name, e.g.
+// {"cache":{"path":"/home/me/.cache"},"runtime":{"error":"..."}}.
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	m := make(map[Kind]snapshotEntry, len(s.entries))
+	for _, k := range Kinds() {
+		m[k] = s.entries[k]
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON decodes a snapshot encoded by MarshalJSON. Kinds missing from
+// the input are recorded as empty.
+func (s *Snapshot) UnmarshalJSON(data []byte) error {
+	var m map[Kind]snapshotEntry
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	var decoded Snapshot
+	for k, e := range m {
+		decoded.entries[k] = e
+	}
+	*s = decoded
+	return nil
+}
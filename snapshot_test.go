@@ -0,0 +1,70 @@
+package dirs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	capture := func(env map[string]string) Snapshot {
+		t.Helper()
+		d, err := NewDirsFor(Linux, MapEnv(env))
+		if err != nil {
+			t.Fatalf("NewDirsFor returned error: %v", err)
+		}
+		return Capture(d)
+	}
+
+	before := capture(map[string]string{"HOME": "/home/me"})
+	if path := before.Path(Cache); path != "/home/me/.cache" {
+		t.Errorf("Expected cache /home/me/.cache, got %s", path)
+	}
+	if before.Err(Runtime) == "" || before.Path(Runtime) != "" {
+		t.Errorf("Expected runtime to be recorded as an error, got (%s, %s)", before.Path(Runtime), before.Err(Runtime))
+	}
+	if !before.Equal(capture(map[string]string{"HOME": "/home/me"})) {
+		t.Error("Expected snapshots of the same environment to be equal")
+	}
+
+	t.Run("Diff", func(t *testing.T) {
+		after := capture(map[string]string{"HOME": "/home/me", "XDG_CACHE_HOME": "/var/cache/me", "XDG_RUNTIME_DIR": "/run/user/1000"})
+		changes := before.Diff(after)
+		if len(changes) != 2 {
+			t.Fatalf("Expected 2 changes, got %v", changes)
+		}
+		if changes[0].Kind != Cache || changes[0].Old != "/home/me/.cache" || changes[0].New != "/var/cache/me" {
+			t.Errorf("Unexpected cache change: %v", changes[0])
+		}
+		if changes[1].Kind != Runtime || changes[1].OldErr == "" || changes[1].New != "/run/user/1000" {
+			t.Errorf("Unexpected runtime change: %v", changes[1])
+		}
+		if s := changes[0].String(); s != "cache: /home/me/.cache -> /var/cache/me" {
+			t.Errorf("Unexpected change string: %s", s)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(before)
+		if err != nil {
+			t.Fatalf("Marshal returned error: %v", err)
+		}
+		if !strings.Contains(string(data), `"cache":{"path":"/home/me/.cache"}`) {
+			t.Errorf("Unexpected JSON: %s", data)
+		}
+
+		var decoded Snapshot
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal returned error: %v", err)
+		}
+		if !decoded.Equal(before) {
+			t.Errorf("Round trip changed the snapshot: %v", before.Diff(decoded))
+		}
+
+		if err := json.Unmarshal([]byte(`{"temp":{"path":"/tmp"}}`), &decoded); err == nil {
+			t.Error("Expected an error for an unknown kind")
+		}
+	})
+}
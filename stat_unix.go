@@ -0,0 +1,17 @@
+//go:build unix
+
+package dirs
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// fileUID returns the uid of the owner of fi, if the platform records one.
+func fileUID(fi fs.FileInfo) (int, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return int(st.Uid), true
+}
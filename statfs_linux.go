@@ -0,0 +1,28 @@
+//go:build linux
+
+package dirs
+
+import "syscall"
+
+// Magic numbers of network filesystems, from statfs(2).
+var networkFilesystems = map[uint32]string{
+	0x6969:     "nfs",
+	0x517b:     "smb",
+	0xfe534d42: "smb2",
+	0xff534d42: "cifs",
+	0x5346414f: "afs",
+	0x73757245: "coda",
+	0x564c:     "ncp",
+	0x00c36400: "ceph",
+	0x01021997: "9p",
+}
+
+// networkFilesystem returns the name of the network filesystem path is on,
+// or an empty string if it is on a local filesystem.
+func networkFilesystem(path string) string {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return ""
+	}
+	return networkFilesystems[uint32(st.Type)]
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package dirs
+
+// networkFilesystem returns the name of the network filesystem path is on.
+// Only Linux layouts validate this, so other hosts report none.
+func networkFilesystem(path string) string {
+	return ""
+}
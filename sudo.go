@@ -0,0 +1,92 @@
+package dirs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ForInvokingUser returns the directories of the user who ran the program
+// through sudo, pkexec or doas, so that a CLI run with sudo reads and writes
+// the invoking user's files rather than root's. The user is identified by
+// SUDO_UID, SUDO_USER, PKEXEC_UID or DOAS_USER, in that order, and resolved
+// as by ForUser: the XDG variables of the process environment, which may be
+// root's, are ignored unless opts include WithEnv, e.g.
+// WithEnv(os.LookupEnv) after sudo -E.
+//
+// If the program is not running as root, none of the variables is set, or
+// it runs in secure mode (see WithSecureMode), ForInvokingUser returns the
+// current user's directories, like New.
+// Otherwise it fails like ForUser, including with ErrNotSupported on
+// platforms other than Linux. Use EnsureDir, which creates directories owned
+// by the invoking user, and Chown for files the program creates itself.
+func ForInvokingUser(opts ...Option) (Dirs, error) {
+	o := newOptions(opts)
+	desc, match, ok := o.invokingUser(os.Geteuid())
+	// A setuid program cannot trust the variables, which its caller sets.
+	if !ok || o.secure || privileged() {
+		return New(opts...), nil
+	}
+	return forUser(desc, match, opts)
+}
+
+// invokingUser identifies the user who gained root privileges through a
+// privilege escalation tool, if the effective uid euid is root.
+func (o *options) invokingUser(euid int) (desc string, match func(passwdEntry) bool, ok bool) {
+	if euid != 0 {
+		return "", nil, false
+	}
+	for _, v := range []string{"SUDO_UID", "SUDO_USER", "PKEXEC_UID", "DOAS_USER"} {
+		value := o.getenv(v)
+		if value == "" {
+			continue
+		}
+		desc = fmt.Sprintf("%s=%s", v, value)
+		if v == "SUDO_USER" || v == "DOAS_USER" {
+			return desc, func(e passwdEntry) bool { return e.name == value }, true
+		}
+		if uid, err := strconv.Atoi(value); err == nil {
+			return desc, func(e passwdEntry) bool { return e.uid == uid }, true
+		}
+	}
+	return "", nil, false
+}
+
+// Chown changes the owner of path to the user whose directories l resolves,
+// as selected by ForUser, ForUID or ForInvokingUser, so that files a program
+// running as root creates there remain usable by that user. Symlinks are not
+// followed. For the current user's directories Chown does nothing.
+func Chown(l Locator, path string) error {
+	u := ownerOf(l)
+	if u == nil {
+		return nil
+	}
+	return os.Lchown(path, u.uid, u.gid)
+}
+
+// ownerOf returns the user whose directories l resolves, or nil for the
+// current user.
+func ownerOf(l Locator) *passwdEntry {
+	switch l := l.(type) {
+	case *layoutDirs:
+		return l.owner()
+	case *ProjectDirs:
+		return ownerOf(l.base)
+	}
+	return nil
+}
+
+// owner returns the user selected by ForUser, or nil for the current user.
+func (o *options) owner() *passwdEntry {
+	return o.user
+}
+
+// mkdirAllOwned is os.MkdirAll, except that the directories it creates are
+// owned by u, if u is not nil, and that then no symlink u could have planted
+// on the way to dir is followed.
+func mkdirAllOwned(dir string, mode os.FileMode, u *passwdEntry) error {
+	if u == nil {
+		return os.MkdirAll(dir, mode)
+	}
+	return mkdirAllAs(dir, mode, u)
+}
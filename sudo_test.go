@@ -0,0 +1,34 @@
+package dirs
+
+import "testing"
+
+func TestInvokingUser(t *testing.T) {
+	entry := passwdEntry{name: "alice", uid: 1500}
+	tests := []struct {
+		name  string
+		env   map[string]string
+		euid  int
+		desc  string
+		match bool
+	}{
+		{"not root", map[string]string{"SUDO_UID": "1500"}, 1000, "", false},
+		{"no variables", nil, 0, "", false},
+		{"sudo uid", map[string]string{"SUDO_UID": "1500", "SUDO_USER": "bob"}, 0, "SUDO_UID=1500", true},
+		{"sudo user", map[string]string{"SUDO_USER": "alice"}, 0, "SUDO_USER=alice", true},
+		{"pkexec", map[string]string{"PKEXEC_UID": "1501"}, 0, "PKEXEC_UID=1501", false},
+		{"doas", map[string]string{"DOAS_USER": "alice"}, 0, "DOAS_USER=alice", true},
+		{"malformed uid", map[string]string{"SUDO_UID": "x", "DOAS_USER": "alice"}, 0, "DOAS_USER=alice", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOptions([]Option{WithEnv(MapEnv(tt.env))})
+			desc, match, ok := o.invokingUser(tt.euid)
+			if ok != (tt.desc != "") || desc != tt.desc {
+				t.Fatalf("Expected %q, got (%q, %v)", tt.desc, desc, ok)
+			}
+			if ok && match(entry) != tt.match {
+				t.Errorf("Expected match(alice) to be %v", tt.match)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package dirs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// AppDirs is implemented by ProjectDirs and SystemDirs, so that the same
+// application code can use either its per-user directories, as a user
+// tool, or its system-wide ones, as a daemon.
+type AppDirs interface {
+	Locator
+	Resolve(kind Kind) (Resolution, error)
+	EnsureDir(kind Kind) (string, error)
+}
+
+var (
+	_ AppDirs = (*ProjectDirs)(nil)
+	_ AppDirs = (*SystemDirs)(nil)
+)
+
+// SystemDirs provides the system-wide directories of a service that runs as
+// root or as a dedicated system user, following the Filesystem Hierarchy
+// Standard: /etc/myapp, /var/lib/myapp, /var/cache/myapp, /var/log/myapp and
+// /run/myapp. The paths do not depend on the environment, and are meant for
+// Unix systems.
+type SystemDirs struct {
+	prefix string
+	path   string
+}
+
+// systemDirMode is the mode SystemDirs creates directories with, as usual
+// for the FHS locations.
+const systemDirMode fs.FileMode = 0o755
+
+// SystemOption configures the SystemDirs returned by NewSystemDirs.
+type SystemOption func(*SystemDirs)
+
+// WithPrefix installs the application below prefix instead of in the
+// system locations, as with the --prefix of an autotools build: with
+// "/usr/local" the configuration directory is /usr/local/etc/myapp and the
+// variable directories are below /usr/local/var, and the runtime directory
+// is /usr/local/run/myapp. The prefixes "", "/" and "/usr" mean the system
+// locations.
+func WithPrefix(prefix string) SystemOption {
+	return func(s *SystemDirs) {
+		s.prefix = path.Clean("/" + prefix)
+	}
+}
+
+// NewSystemDirs returns the system-wide directories of application. The
+// directory name is derived from application as on Linux, e.g. "myapp" for
+// "My App".
+func NewSystemDirs(application string, opts ...SystemOption) (*SystemDirs, error) {
+	if strings.TrimSpace(application) == "" {
+		return nil, errors.New("dirs: application name must not be empty")
+	}
+	s := &SystemDirs{prefix: "/", path: xdgProjectPath("", "", strings.TrimSpace(application))}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// ProjectPath returns the name of the application's directories, e.g.
+// "myapp".
+func (s *SystemDirs) ProjectPath() string {
+	return s.path
+}
+
+// Dir returns the system directory of the given kind. The kinds are the same
+// as for ProjectDirs: Config and Preference are the configuration directory,
+// Data, DataLocal and State the variable state directory, and Cache and
+// Runtime their own directories. Other kinds report ErrNotSupported.
+func (s *SystemDirs) Dir(kind Kind) (string, error) {
+	res, err := s.Resolve(kind)
+	return res.Path, err
+}
+
+// Resolve is like Dir but also explains where the directory came from.
+func (s *SystemDirs) Resolve(kind Kind) (Resolution, error) {
+	res := Resolution{Kind: kind}
+	var rule string
+	switch kind {
+	case Config, Preference:
+		rule = "etc"
+	case Data, DataLocal, State:
+		rule = "var/lib"
+	case Cache:
+		rule = "var/cache"
+	case Runtime:
+		rule = "run"
+	default:
+		return res, &DirError{Kind: kind, Err: ErrNotSupported}
+	}
+	res.use(SourceDefault, s.detail(rule), s.join(rule))
+	return res, nil
+}
+
+// EnsureDir is like Dir but also creates the directory, and any missing
+// parents, with mode 0755. Unlike per-user directories, system directories
+// are shared, e.g. with the service user a daemon drops its privileges to,
+// so the mode of an existing directory is left to the administrator.
+func (s *SystemDirs) EnsureDir(kind Kind) (string, error) {
+	dir, err := s.Dir(kind)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, systemDirMode); err != nil {
+		return "", &DirError{Kind: kind, Err: err}
+	}
+	return dir, nil
+}
+
+func (s *SystemDirs) CacheDir() (string, error)      { return s.Dir(Cache) }
+func (s *SystemDirs) ConfigDir() (string, error)     { return s.Dir(Config) }
+func (s *SystemDirs) DataDir() (string, error)       { return s.Dir(Data) }
+func (s *SystemDirs) DataLocalDir() (string, error)  { return s.Dir(DataLocal) }
+func (s *SystemDirs) PreferenceDir() (string, error) { return s.Dir(Preference) }
+func (s *SystemDirs) RuntimeDir() (string, error)    { return s.Dir(Runtime) }
+func (s *SystemDirs) StateDir() (string, error)      { return s.Dir(State) }
+
+// LogDir returns the log directory, e.g. /var/log/myapp. It has no Kind, as
+// per-user logs belong in the state directory.
+func (s *SystemDirs) LogDir() string {
+	return s.join("var/log")
+}
+
+// EnsureLogDir is like LogDir but also creates the directory, as EnsureDir
+// does.
+func (s *SystemDirs) EnsureLogDir() (string, error) {
+	dir := s.LogDir()
+	if err := os.MkdirAll(dir, systemDirMode); err != nil {
+		return "", fmt.Errorf("dirs: log: %w", err)
+	}
+	return dir, nil
+}
+
+// join returns the application's directory below the system directory
+// rule, e.g. "var/lib", relative to the prefix.
+func (s *SystemDirs) join(rule string) string {
+	if s.system() {
+		return path.Join("/", rule, s.path)
+	}
+	return path.Join(s.prefix, rule, s.path)
+}
+
+// detail describes the rule for Resolution.Detail, e.g. "/var/lib/<app>".
+func (s *SystemDirs) detail(rule string) string {
+	if s.system() {
+		return "/" + rule + "/<app>"
+	}
+	return "<prefix>/" + rule + "/<app>"
+}
+
+// system reports whether the prefix selects the system locations.
+func (s *SystemDirs) system() bool {
+	return s.prefix == "/" || s.prefix == "/usr"
+}
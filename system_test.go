@@ -0,0 +1,110 @@
+package dirs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSystemDirs(t *testing.T) {
+	if _, err := NewSystemDirs(" "); err == nil {
+		t.Error("Expected an error for an empty application name")
+	}
+
+	tests := []struct {
+		name     string
+		opts     []SystemOption
+		expected map[Kind]string
+		log      string
+	}{
+		{"fhs", nil, map[Kind]string{
+			Config:     "/etc/myapp",
+			Preference: "/etc/myapp",
+			Data:       "/var/lib/myapp",
+			State:      "/var/lib/myapp",
+			Cache:      "/var/cache/myapp",
+			Runtime:    "/run/myapp",
+		}, "/var/log/myapp"},
+		{"usr prefix", []SystemOption{WithPrefix("/usr")}, map[Kind]string{
+			Config: "/etc/myapp",
+		}, "/var/log/myapp"},
+		{"local prefix", []SystemOption{WithPrefix("/usr/local/")}, map[Kind]string{
+			Config:  "/usr/local/etc/myapp",
+			Data:    "/usr/local/var/lib/myapp",
+			Cache:   "/usr/local/var/cache/myapp",
+			Runtime: "/usr/local/run/myapp",
+		}, "/usr/local/var/log/myapp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSystemDirs("My App", tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for kind, expected := range tt.expected {
+				res, err := s.Resolve(kind)
+				if err != nil || res.Path != expected || res.Source != SourceDefault {
+					t.Errorf("%s: expected %s, got (%v, %v)", kind, expected, res, err)
+				}
+			}
+			if log := s.LogDir(); log != tt.log {
+				t.Errorf("Expected log dir %s, got %s", tt.log, log)
+			}
+		})
+	}
+
+	s, err := NewSystemDirs("myapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Dir(Audio); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported for a user folder, got %v", err)
+	}
+}
+
+func TestSystemDirsEnsureDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SystemDirs uses Unix paths")
+	}
+	prefix := t.TempDir()
+	s, err := NewSystemDirs("myapp", WithPrefix(prefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(prefix, "etc", "myapp")
+	if err := os.MkdirAll(config, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	// Set the mode explicitly, as MkdirAll is subject to the umask.
+	if err := os.Chmod(config, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if dir, err := s.EnsureDir(Config); err != nil || dir != config {
+		t.Fatalf("Expected %s, got (%s, %v)", config, dir, err)
+	}
+	if fi, err := os.Stat(config); err != nil || fi.Mode().Perm() != 0o755 {
+		t.Errorf("Expected the mode of %s to be left at 0755, got (%v, %v)", config, fi, err)
+	}
+
+	cache, err := s.EnsureDir(Cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := s.EnsureLogDir()
+	if err != nil || log != filepath.Join(prefix, "var", "log", "myapp") {
+		t.Fatalf("Expected the log dir below %s, got (%s, %v)", prefix, log, err)
+	}
+	for _, dir := range []string{cache, log} {
+		fi, err := os.Stat(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Creation modes are subject to the umask, which can only remove
+		// bits, so only check that the directory is not private.
+		if fi.Mode().Perm()&0o005 == 0 {
+			t.Errorf("Expected %s to be created with mode 0755, got %04o", dir, fi.Mode().Perm())
+		}
+	}
+}
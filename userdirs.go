@@ -0,0 +1,98 @@
+package dirs
+
+import (
+	"bufio"
+	"io"
+	"path"
+	"strings"
+)
+
+// userDirsFile is the name of the file, relative to the config directory,
+// in which xdg-user-dirs-update records the well-known user directories.
+const userDirsFile = "user-dirs.dirs"
+
+// userDirsEntry is a directory read from a user-dirs.dirs file.
+type userDirsEntry struct {
+	path string
+	line int // 1-based line number, for provenance
+}
+
+// parseUserDirs parses the contents of a user-dirs.dirs file.
+//
+// The format is a restricted shell syntax: each meaningful line has the form
+// XDG_NAME_DIR="value", where value is either an absolute path or a path
+// starting with $HOME, and backslash escapes the following character. This
+// mirrors the parser used by xdg-user-dir-lookup; lines that do not follow
+// these rules are ignored rather than reported. The returned map is keyed by
+// variable name (e.g. "XDG_MUSIC_DIR") with $HOME expanded to home.
+func parseUserDirs(r io.Reader, home string) (map[string]userDirsEntry, error) {
+	dirs := make(map[string]userDirsEntry)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if key, value, ok := parseUserDirsLine(scanner.Text(), home); ok {
+			dirs[key] = userDirsEntry{path: value, line: line}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return dirs, nil
+}
+
+// parseUserDirsLine parses a single line of a user-dirs.dirs file.
+func parseUserDirsLine(line, home string) (key, value string, ok bool) {
+	line = strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(line, "XDG_") {
+		return "", "", false
+	}
+	eq := strings.IndexByte(line, '=')
+	if eq < 0 {
+		return "", "", false
+	}
+	key = strings.TrimRight(line[:eq], " \t")
+	if !strings.HasSuffix(key, "_DIR") || strings.ContainsAny(key, " \t") {
+		return "", "", false
+	}
+	rest := strings.TrimLeft(line[eq+1:], " \t")
+	if !strings.HasPrefix(rest, `"`) {
+		return "", "", false
+	}
+	rest = rest[1:]
+
+	relativeToHome := false
+	switch {
+	case strings.HasPrefix(rest, "$HOME/"):
+		relativeToHome = true
+		rest = rest[len("$HOME/"):]
+	case strings.HasPrefix(rest, `$HOME"`):
+		relativeToHome = true
+		rest = rest[len("$HOME"):]
+	case strings.HasPrefix(rest, "/"):
+	default:
+		return "", "", false
+	}
+
+	var b strings.Builder
+	closed := false
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		if c == '"' {
+			closed = true
+			break
+		}
+		if c == '\\' && i+1 < len(rest) {
+			i++
+			c = rest[i]
+		}
+		b.WriteByte(c)
+	}
+	if !closed {
+		return "", "", false
+	}
+
+	value = b.String()
+	if relativeToHome {
+		value = path.Join(home, value)
+	}
+	return key, value, true
+}
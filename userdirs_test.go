@@ -0,0 +1,49 @@
+package dirs
+
+import (
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestParseUserDirs(t *testing.T) {
+	home := "/home/tester"
+	input := `# This file is written by xdg-user-dirs-update
+# If you want to change or add directories, just edit the line you're
+# interested in. All local changes will be retained on the next run.
+XDG_DESKTOP_DIR="$HOME/Desktop"
+XDG_DOWNLOAD_DIR="$HOME/Téléchargements"
+  XDG_MUSIC_DIR = "$HOME/My Music"
+XDG_PICTURES_DIR="/srv/pictures"
+XDG_PUBLICSHARE_DIR="$HOME/"
+XDG_TEMPLATES_DIR="$HOME/Quoted \"Templates\""
+XDG_VIDEOS_DIR=$HOME/Unquoted
+XDG_DOCUMENTS_DIR="relative/Documents"
+XDG_BROKEN_DIR="$HOME/missing-quote
+NOT_XDG_DIR="$HOME/Other"
+`
+	got, err := parseUserDirs(strings.NewReader(input), home)
+	if err != nil {
+		t.Fatalf("parseUserDirs returned error: %v", err)
+	}
+
+	want := map[string]string{
+		"XDG_DESKTOP_DIR":     path.Join(home, "Desktop"),
+		"XDG_DOWNLOAD_DIR":    path.Join(home, "Téléchargements"),
+		"XDG_MUSIC_DIR":       path.Join(home, "My Music"),
+		"XDG_PICTURES_DIR":    "/srv/pictures",
+		"XDG_PUBLICSHARE_DIR": home,
+		"XDG_TEMPLATES_DIR":   path.Join(home, `Quoted "Templates"`),
+	}
+	if line := got["XDG_MUSIC_DIR"].line; line != 6 {
+		t.Errorf("XDG_MUSIC_DIR expected on line 6, got %d", line)
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected %d entries, got %d: %v", len(want), len(got), got)
+	}
+	for key, expected := range want {
+		if got[key].path != expected {
+			t.Errorf("%s expected '%s', got '%s'", key, expected, got[key].path)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package dirs
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+type windowsDirs struct {
+	*options
+}
+
+func (d *windowsDirs) resolve(kind Kind) (Resolution, error) {
+	switch kind {
+	case Home:
+		return d.resolveHome("USERPROFILE")
+	case Cache, DataLocal:
+		return d.getKnownFolder(kind, "LOCALAPPDATA", "AppData", "Local")
+	case Config, Data, Preference:
+		return d.getKnownFolder(kind, "APPDATA", "AppData", "Roaming")
+	case Executable, Runtime, State, Font:
+		return d.unsupported(kind) // Not standard on Windows
+	case Audio:
+		return d.getUserDir(kind, "Music")
+	case Desktop:
+		return d.getUserDir(kind, "Desktop")
+	case Document:
+		return d.getUserDir(kind, "Documents")
+	case Download:
+		return d.getUserDir(kind, "Downloads")
+	case Picture:
+		return d.getUserDir(kind, "Pictures")
+	case Public:
+		res := Resolution{Kind: kind}
+		if dir := d.getenv("PUBLIC"); dir != "" {
+			res.use(SourceEnv, "PUBLIC", dir)
+			return res, nil
+		}
+		res.skip(SourceEnv, "PUBLIC", "", "not set")
+		return d.notSet(res, "PUBLIC")
+	case Template:
+		res := Resolution{Kind: kind}
+		appData, err := d.resolve(Config)
+		if err != nil {
+			return res, err
+		}
+		res.derive(SourceDerived, `config\Microsoft\Windows\Templates`, d.join(appData.Path, "Microsoft", "Windows", "Templates"), appData)
+		return res, nil
+	case Video:
+		return d.getUserDir(kind, "Videos")
+	}
+	return d.unsupported(kind)
+}
+
+// getKnownFolder returns the folder named by envVar, falling back to its
+// default location under the user profile when the variable is not set.
+func (d *windowsDirs) getKnownFolder(kind Kind, envVar string, defaultSubPath ...string) (Resolution, error) {
+	res := Resolution{Kind: kind}
+	if dir := d.getenv(envVar); dir != "" {
+		res.use(SourceEnv, envVar, dir)
+		return res, nil
+	}
+	res.skip(SourceEnv, envVar, "", "not set")
+	return d.getHomeSubDir(res, defaultSubPath...)
+}
+
+// getUserDir returns a folder directly under the user profile.
+func (d *windowsDirs) getUserDir(kind Kind, subPath string) (Resolution, error) {
+	return d.getHomeSubDir(Resolution{Kind: kind}, subPath)
+}
+
+// getHomeSubDir resolves res to the default path subPath under the user
+// profile.
+func (d *windowsDirs) getHomeSubDir(res Resolution, subPath ...string) (Resolution, error) {
+	home, err := d.resolve(Home)
+	if err != nil {
+		return res, err
+	}
+	detail := strings.Join(append([]string{"%USERPROFILE%"}, subPath...), `\`)
+	res.derive(SourceDefault, detail, d.join(append([]string{home.Path}, subPath...)...), home)
+	return res, nil
+}
+
+func (d *windowsDirs) systemConfigDirs() ([]string, error) {
+	return d.getProgramDataDirs(), nil
+}
+
+func (d *windowsDirs) systemDataDirs() ([]string, error) {
+	return d.getProgramDataDirs(), nil
+}
+
+// getProgramDataDirs returns %PROGRAMDATA% as a single-entry search path, or
+// no entries if the variable is not set.
+func (d *windowsDirs) getProgramDataDirs() []string {
+	if dir := d.getenv("PROGRAMDATA"); dir != "" {
+		return []string{dir}
+	}
+	return nil
+}
+
+func (d *windowsDirs) platform() Platform {
+	return Windows
+}
+
+func (d *windowsDirs) projectPath(qualifier, organization, application string) string {
+	return windowsProjectPath(qualifier, organization, application)
+}
+
+func (d *windowsDirs) join(elem ...string) string {
+	return windowsJoin(elem...)
+}
+
+func (d *windowsDirs) isAbs(dir string) bool {
+	return windowsIsAbs(dir)
+}
+
+// windowsJoin joins path elements with backslashes. On Windows it is
+// filepath.Join; elsewhere it performs the subset of that cleaning needed
+// for the paths built here, so that Windows layouts can be computed on any
+// host.
+func windowsJoin(elem ...string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(elem...)
+	}
+	var parts []string
+	for _, e := range elem {
+		if e != "" {
+			parts = append(parts, e)
+		}
+	}
+	joined := strings.ReplaceAll(strings.Join(parts, `\`), "/", `\`)
+
+	// Collapse repeated separators, preserving a leading UNC prefix.
+	prefix := ""
+	if strings.HasPrefix(joined, `\\`) {
+		prefix, joined = `\\`, joined[2:]
+	}
+	for strings.Contains(joined, `\\`) {
+		joined = strings.ReplaceAll(joined, `\\`, `\`)
+	}
+	return prefix + joined
+}
+
+// windowsIsAbs reports whether p is an absolute Windows path: either a drive
+// letter followed by a separator, or a UNC path.
+func windowsIsAbs(p string) bool {
+	if strings.HasPrefix(p, `\\`) || strings.HasPrefix(p, "//") {
+		return true
+	}
+	return len(p) >= 3 && p[1] == ':' && (p[2] == '\\' || p[2] == '/') &&
+		('a' <= p[0] && p[0] <= 'z' || 'A' <= p[0] && p[0] <= 'Z')
+}